@@ -0,0 +1,62 @@
+package api
+
+import "net/http"
+
+// The generated response types only have methods for the status. Header is written by hand for the
+// responses that are retried, so the retry policy can read Retry-After without depending on field names
+
+// Header returns HTTPResponse.Header
+func (r GetUserPublishedArticlesResponse) Header() http.Header {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Header
+	}
+	return http.Header{}
+}
+
+// Header returns HTTPResponse.Header
+func (r GetUserUnpublishedArticlesResponse) Header() http.Header {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Header
+	}
+	return http.Header{}
+}
+
+// Header returns HTTPResponse.Header
+func (r GetOrgArticlesResponse) Header() http.Header {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Header
+	}
+	return http.Header{}
+}
+
+// Header returns HTTPResponse.Header
+func (r GetOrganizationResponse) Header() http.Header {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Header
+	}
+	return http.Header{}
+}
+
+// Header returns HTTPResponse.Header
+func (r UpdateArticleResponse) Header() http.Header {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Header
+	}
+	return http.Header{}
+}
+
+// Header returns HTTPResponse.Header
+func (r GetArticleByIdResponse) Header() http.Header {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Header
+	}
+	return http.Header{}
+}
+
+// Header returns HTTPResponse.Header
+func (r CreateArticleResponse) Header() http.Header {
+	if r.HTTPResponse != nil {
+		return r.HTTPResponse.Header
+	}
+	return http.Header{}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// syncState describes which side changed since the last synchronization
+type syncState string
+
+const (
+	syncStateLocalChange  syncState = "local-change"
+	syncStateRemoteChange syncState = "remote-change"
+	syncStateConflict     syncState = "conflict"
+)
+
+// syncHash creates a hash of the article contents that are compared when synchronizing. It is
+// stored in article.json after each sync and used as the baseline to detect which side changed
+func syncHash(body, title string, tags []string) string {
+	if tags == nil {
+		tags = []string{}
+	}
+
+	data, _ := json.Marshal(struct {
+		Body  string   `json:"body"`
+		Title string   `json:"title"`
+		Tags  []string `json:"tags"`
+	}{body, title, tags})
+
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// classifyChange compares the local and remote hashes to the baseline from the last sync. Articles
+// without a baseline are always treated as local changes, which was the behavior before baselines
+// were recorded
+func classifyChange(baseline, local, remote string) syncState {
+	switch {
+	case baseline == "" || local == remote || remote == baseline:
+		return syncStateLocalChange
+	case local == baseline:
+		return syncStateRemoteChange
+	default:
+		return syncStateConflict
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestClassifyChange(t *testing.T) {
+	tests := []struct {
+		name     string
+		baseline string
+		local    string
+		remote   string
+		expected syncState
+	}{
+		{"NoBaseline", "", "local", "remote", syncStateLocalChange},
+		{"LocalChange", "base", "local", "base", syncStateLocalChange},
+		{"RemoteChange", "base", "base", "remote", syncStateRemoteChange},
+		{"Conflict", "base", "local", "remote", syncStateConflict},
+		{"SameChangeOnBothSides", "base", "same", "same", syncStateLocalChange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := classifyChange(tt.baseline, tt.local, tt.remote)
+			if result != tt.expected {
+				t.Fatalf("unexpected result: %s", result)
+			}
+		})
+	}
+}
+
+func TestSyncHash(t *testing.T) {
+	if syncHash("body", "title", nil) != syncHash("body", "title", []string{}) {
+		t.Fatal("expected nil and empty tags to have the same hash")
+	}
+
+	if syncHash("body", "title", []string{"go"}) == syncHash("body", "title", []string{"golang"}) {
+		t.Fatal("expected different tags to have different hashes")
+	}
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// redactedHeaders are replaced in the cassette so it can be shared in bug reports
+var redactedHeaders = []string{"Api-Key", "Authorization"}
+
+const redacted = "REDACTED"
+
+// interaction is one line of a cassette. The URL only has the path and query so a cassette
+// recorded against dev.to can be replayed with any base URL
+type interaction struct {
+	Method         string      `json:"method"`
+	URL            string      `json:"url"`
+	RequestHeader  http.Header `json:"request_header,omitempty"`
+	RequestBody    string      `json:"request_body,omitempty"`
+	Status         int         `json:"status,omitempty"`
+	ResponseHeader http.Header `json:"response_header,omitempty"`
+	ResponseBody   string      `json:"response_body,omitempty"`
+	Error          string      `json:"error,omitempty"`
+	Duration       string      `json:"duration"`
+}
+
+// cassetteRecorder is an http.RoundTripper that writes every request and response to a JSON lines file
+type cassetteRecorder struct {
+	next http.RoundTripper
+
+	mu  sync.Mutex
+	out io.WriteCloser
+}
+
+// newCassetteRecorder creates the cassette file, replacing an existing one
+func newCassetteRecorder(path string, next http.RoundTripper) (*cassetteRecorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("error creating cassette: %w", err)
+	}
+
+	return &cassetteRecorder{next: next, out: f}, nil
+}
+
+func (r *cassetteRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading request body: %w", err)
+	}
+
+	i := interaction{
+		Method:        req.Method,
+		URL:           req.URL.RequestURI(),
+		RequestHeader: redactHeader(req.Header),
+		RequestBody:   string(requestBody),
+	}
+
+	start := time.Now()
+	resp, err := r.next.RoundTrip(req)
+	i.Duration = time.Since(start).String()
+	if err != nil {
+		i.Error = err.Error()
+		return nil, errors.Join(err, r.write(i))
+	}
+
+	// the response isn't returned when recording fails, so its body is closed to release the connection
+	responseBody, err := readBody(&resp.Body)
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	i.Status = resp.StatusCode
+	i.ResponseHeader = redactHeader(resp.Header)
+	i.ResponseBody = string(responseBody)
+
+	err = r.write(i)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *cassetteRecorder) write(i interaction) error {
+	data, err := json.Marshal(i)
+	if err != nil {
+		return fmt.Errorf("error marshaling interaction: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, err = r.out.Write(append(data, '\n'))
+	if err != nil {
+		return fmt.Errorf("error writing cassette: %w", err)
+	}
+
+	return nil
+}
+
+func (r *cassetteRecorder) Close() error {
+	return r.out.Close()
+}
+
+// cassettePlayer is an http.RoundTripper that responds with interactions from a cassette instead of
+// sending requests. Each request uses the first unused interaction with the same method, URL, and body,
+// so retries are replayed in the recorded order even when articles are synchronized concurrently
+type cassettePlayer struct {
+	mu           sync.Mutex
+	interactions []*interaction
+	used         []bool
+}
+
+func newCassettePlayer(path string) (*cassettePlayer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening cassette: %w", err)
+	}
+	defer f.Close()
+
+	player := &cassettePlayer{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var i interaction
+		err = json.Unmarshal(scanner.Bytes(), &i)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing cassette line %d: %w", line, err)
+		}
+		player.interactions = append(player.interactions, &i)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading cassette: %w", err)
+	}
+
+	player.used = make([]bool, len(player.interactions))
+
+	return player, nil
+}
+
+func (p *cassettePlayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading request body: %w", err)
+	}
+
+	i, err := p.next(req.Method, req.URL.RequestURI(), string(requestBody))
+	if err != nil {
+		return nil, err
+	}
+
+	if i.Error != "" {
+		return nil, fmt.Errorf("recorded error: %s", i.Error)
+	}
+
+	header := i.ResponseHeader
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.Status, http.StatusText(i.Status)),
+		StatusCode:    i.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header.Clone(),
+		Body:          io.NopCloser(bytes.NewBufferString(i.ResponseBody)),
+		ContentLength: int64(len(i.ResponseBody)),
+		Request:       req,
+	}, nil
+}
+
+func (p *cassettePlayer) next(method, url, body string) (*interaction, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for n, i := range p.interactions {
+		if p.used[n] || i.Method != method || i.URL != url || i.RequestBody != body {
+			continue
+		}
+		p.used[n] = true
+		return i, nil
+	}
+
+	return nil, fmt.Errorf("no recorded interaction for %s %s", method, url)
+}
+
+// unused returns the number of interactions that were not replayed, which means the run differed from the recording
+func (p *cassettePlayer) unused() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	count := 0
+	for _, used := range p.used {
+		if !used {
+			count++
+		}
+	}
+	return count
+}
+
+// readBody reads the whole body and replaces it so it can be read again
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(*body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = (*body).Close()
+	if err != nil {
+		return nil, err
+	}
+
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+func redactHeader(header http.Header) http.Header {
+	result := header.Clone()
+	for _, name := range redactedHeaders {
+		if result.Get(name) != "" {
+			result.Set(name, redacted)
+		}
+	}
+	return result
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/calvinmclean/article-sync/api"
+	"github.com/calvinmclean/article-sync/fakeforem"
+)
+
+func TestCassetteRecordAndReplay(t *testing.T) {
+	forem := fakeforem.New(testAPIKey, "tester")
+	forem.InjectFault(fakeforem.Fault{Method: "POST", Status: 429, RetryAfter: "1", Times: 1})
+	server := forem.Start()
+	defer server.Close()
+
+	cassette := filepath.Join(t.TempDir(), "cassette.jsonl")
+	recorder, err := newCassetteRecorder(cassette, http.DefaultTransport)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	recordRoot := t.TempDir()
+	recordDir := writeTestArticle(t, recordRoot, "my-article", &Article{Title: "My Article"}, "Hello")
+
+	c := newTestClient(t, server.URL, false, api.WithHTTPClient(&http.Client{Transport: recorder}))
+	syncTestArticles(t, c, recordRoot)
+
+	err = recorder.Close()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(cassette)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(data), testAPIKey) {
+		t.Fatalf("expected API key to be redacted: %s", data)
+	}
+
+	// replay uses a different URL to show that nothing is sent to the server
+	player, err := newCassettePlayer(cassette)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	replayRoot := t.TempDir()
+	replayDir := writeTestArticle(t, replayRoot, "my-article", &Article{Title: "My Article"}, "Hello")
+
+	c = newTestClient(t, "http://replay.invalid", false, api.WithHTTPClient(&http.Client{Transport: player}))
+	syncTestArticles(t, c, replayRoot)
+
+	if player.unused() != 0 {
+		t.Fatalf("expected every interaction to be used but %d were not", player.unused())
+	}
+
+	recorded, err := readArticleFile(recordDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	replayed, err := readArticleFile(replayDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replayed.ID != recorded.ID || replayed.URL != recorded.URL || replayed.SyncHash != recorded.SyncHash {
+		t.Fatalf("expected replay to match recording: %+v %+v", recorded, replayed)
+	}
+
+	// a different local change is not in the cassette
+	err = os.WriteFile(filepath.Join(replayDir, "article.md"), []byte("Changed"), 0640)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = c.syncArticlesFromRootDirectory(context.Background(), replayRoot, &commentData{})
+	if err == nil || !strings.Contains(err.Error(), "no recorded interaction for GET /api/articles/1") {
+		t.Fatalf("expected missing interaction error but got: %v", err)
+	}
+}
+
+// trackedBody records whether it was closed and can fail reads
+type trackedBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *trackedBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+func (failingWriter) Close() error              { return nil }
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestCassetteRecorderClosesBodyOnError(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          io.Reader
+		expectedError string
+	}{
+		{"ReadError", iotest.ErrReader(errors.New("connection reset")), "error reading response body: connection reset"},
+		{"WriteError", strings.NewReader("{}"), "error writing cassette: disk full"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := &trackedBody{Reader: tt.body}
+			recorder := &cassetteRecorder{
+				next: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+					return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: body}, nil
+				}),
+				out: failingWriter{},
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/articles/me", nil)
+			_, err := recorder.RoundTrip(req)
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Fatalf("expected error %q but got: %v", tt.expectedError, err)
+			}
+			if !body.closed {
+				t.Fatal("expected the response body to be closed")
+			}
+		})
+	}
+}
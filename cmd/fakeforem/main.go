@@ -0,0 +1,34 @@
+// fakeforem runs an in-memory Forem API so article-sync can be rehearsed without changing real
+// articles. State is lost when it exits
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/calvinmclean/article-sync/fakeforem"
+)
+
+func main() {
+	var addr, apiKey, username, organization string
+	var organizationID int
+	flag.StringVar(&addr, "addr", "localhost:3000", "address to listen on")
+	flag.StringVar(&apiKey, "api-key", "", "API key required for authenticated requests. Any key is accepted if empty")
+	flag.StringVar(&username, "username", "rehearsal", "username of the article author")
+	flag.StringVar(&organization, "organization", "", "username of an organization to create")
+	flag.IntVar(&organizationID, "organization-id", 1, "ID of the organization to create")
+	flag.Parse()
+
+	server := fakeforem.New(apiKey, username)
+	if organization != "" {
+		server.AddOrganization(fakeforem.Organization{
+			ID:       organizationID,
+			Username: organization,
+			Name:     organization,
+		})
+	}
+
+	log.Printf("listening on http://%s", addr)
+	log.Fatal(http.ListenAndServe(addr, server))
+}
@@ -0,0 +1,730 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/calvinmclean/article-sync/api"
+)
+
+// exit codes used by every command
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// command is a subcommand of the CLI. setup registers the command's flags and returns the function
+// that runs it after the flags are parsed
+type command struct {
+	name    string
+	args    string
+	summary string
+	setup   func(fs *flag.FlagSet) func(args []string) error
+}
+
+var commands = []command{
+	{"sync", "", "create and update articles on dev.to", syncCommand},
+	{"plan", "", "show which articles sync would create and update without changing anything", planCommand},
+	{"init", "", "download published articles from dev.to into article directories", initCommand},
+	{"pull", "", "overwrite local articles with changes made on dev.to", pullCommand},
+	{"status", "", "show whether each article is in sync with dev.to without changing anything", statusCommand},
+	{"validate", "", "check article.json and article.md in every article directory", validateCommand},
+	{"new", "TITLE", "create a directory for a new article", newCommand},
+	{"render", "DIR", "print the body that sync would publish for an article directory", renderCommand},
+	{"cover", "DIR...", "create the gopher cover image for article directories", coverCommand},
+}
+
+// usageError is returned for invalid arguments so the command exits with exitUsage
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+// run executes the command named by the first argument and returns the exit code. If the first
+// argument is a flag or missing, the legacy flags used by the GitHub Action are used instead
+func run(args []string, stderr io.Writer) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") && !slices.Contains([]string{"-h", "-help", "--help"}, args[0]) {
+		return runCommand(legacyCommand, args, stderr)
+	}
+
+	name := args[0]
+	if name == "help" || strings.HasPrefix(name, "-") {
+		if len(args) > 1 {
+			name = args[1]
+			args = []string{name, "-h"}
+		} else {
+			printUsage(stderr)
+			return exitOK
+		}
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return runCommand(cmd, args[1:], stderr)
+		}
+	}
+
+	fmt.Fprintf(stderr, "unknown command %q\n\n", name)
+	printUsage(stderr)
+	return exitUsage
+}
+
+func runCommand(cmd command, args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet(strings.TrimSpace("article-sync "+cmd.name), flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: %s [flags] %s\n\n%s\n\nFlags:\n", fs.Name(), cmd.args, cmd.summary)
+		fs.PrintDefaults()
+	}
+
+	runFunc := cmd.setup(fs)
+
+	err := fs.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if err != nil {
+		return exitUsage
+	}
+
+	if cmd.args == "" && fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		fs.Usage()
+		return exitUsage
+	}
+
+	err = runFunc(fs.Args())
+	var usageErr usageError
+	if errors.As(err, &usageErr) {
+		fmt.Fprintln(stderr, usageErr.msg)
+		fs.Usage()
+		return exitUsage
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	return exitOK
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: article-sync <command> [flags]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(w, "\nRun \"article-sync help <command>\" for the flags of a command.\n")
+}
+
+// apiOptions are the flags for commands that make requests to dev.to
+type apiOptions struct {
+	apiKey, baseURL, organization       string
+	recordPath, replayPath              string
+	concurrency, rateBurst, maxAttempts int
+	rateLimit                           float64
+	maxRetryWait, timeout               time.Duration
+	requestTimeout                      time.Duration
+}
+
+func (o *apiOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.apiKey, "api-key", "", "API key for accessing dev.to. Defaults to env var API_KEY")
+	fs.StringVar(&o.baseURL, "url", "https://dev.to", "base URL of the Forem API. Use with cmd/fakeforem to rehearse a sync")
+	fs.StringVar(&o.organization, "organization", "", "username of the organization to publish articles under unless set in article.json. Used by init to import the organization's articles")
+	fs.IntVar(&o.concurrency, "concurrency", 4, "number of articles to synchronize at the same time")
+	fs.Float64Var(&o.rateLimit, "rate-limit", 2, "maximum average requests per second to dev.to shared by all workers. Use 0 for no limit")
+	fs.IntVar(&o.rateBurst, "rate-burst", 2, "maximum requests to dev.to that can be made at once before --rate-limit applies")
+	fs.IntVar(&o.maxAttempts, "max-attempts", 5, "maximum attempts for each request to dev.to, including retries")
+	fs.DurationVar(&o.maxRetryWait, "max-retry-wait", 60*time.Second, "maximum time to wait between retries")
+	fs.DurationVar(&o.timeout, "timeout", 30*time.Minute, "maximum time for the whole run. Use 0 for no limit")
+	fs.DurationVar(&o.requestTimeout, "request-timeout", 30*time.Second, "maximum time for each request before it is retried")
+	fs.StringVar(&o.recordPath, "record", "", "file to record every request to dev.to and its response into, with the API key redacted")
+	fs.StringVar(&o.replayPath, "replay", "", "file recorded with --record to respond to requests from instead of dev.to")
+}
+
+// context is canceled by the first SIGINT or SIGTERM, which stops starting new work and finishes requests that
+// create or update articles so their IDs are saved. A second signal exits immediately
+func (o *apiOptions) context() (context.Context, context.CancelFunc) {
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigCtx.Done()
+		stop()
+	}()
+
+	if o.timeout <= 0 {
+		return sigCtx, stop
+	}
+
+	ctx, cancel := context.WithTimeout(sigCtx, o.timeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// newClient creates a client from the options. The returned function must be called when the command
+// finishes to close the recording or report unused interactions from the replay
+func (o *apiOptions) newClient(dryRun, createImage bool) (*client, func(), error) {
+	if o.recordPath != "" && o.replayPath != "" {
+		return nil, nil, usageError{"--record and --replay cannot be used together"}
+	}
+
+	if o.apiKey == "" {
+		o.apiKey = os.Getenv("API_KEY")
+		if o.apiKey == "" && o.replayPath == "" {
+			return nil, nil, usageError{"missing required argument --api-key or env var API_KEY"}
+		}
+	}
+
+	var opts []api.ClientOption
+	var recorder *cassetteRecorder
+	var player *cassettePlayer
+	var err error
+	switch {
+	case o.recordPath != "":
+		recorder, err = newCassetteRecorder(o.recordPath, http.DefaultTransport)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error starting recording: %w", err)
+		}
+		opts = append(opts, api.WithHTTPClient(&http.Client{Transport: recorder}))
+	case o.replayPath != "":
+		player, err = newCassettePlayer(o.replayPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error starting replay: %w", err)
+		}
+		opts = append(opts, api.WithHTTPClient(&http.Client{Transport: player}))
+	}
+
+	c, err := newClient(o.baseURL, o.apiKey, dryRun, createImage, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating API client: %w", err)
+	}
+
+	c.organization = o.organization
+	c.concurrency = o.concurrency
+	c.limiter = newRateLimiter(o.rateLimit, o.rateBurst)
+	c.retry.maxAttempts = o.maxAttempts
+	c.retry.maxWait = o.maxRetryWait
+	c.retry.attemptTimeout = o.requestTimeout
+
+	done := func() {
+		if recorder != nil {
+			err := recorder.Close()
+			if err != nil {
+				c.logger.Error("error closing recording", "error", err)
+			}
+		}
+		if player != nil {
+			if unused := player.unused(); unused > 0 {
+				c.logger.Warn("replay did not use every recorded interaction", "unused", unused)
+			}
+		}
+	}
+
+	return c, done, nil
+}
+
+// syncOptions are the flags for sync and plan
+type syncOptions struct {
+	path, prComment, commit, reportPath     string
+	commentTemplatePath, commitTemplatePath string
+	repositoryName, branch                  string
+	seriesFooter, force, keepGoing          bool
+	embedLinks                              bool
+}
+
+func (o *syncOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.path, "path", "./articles", "root path to scan for articles")
+	fs.StringVar(&o.prComment, "pr-comment", "", "file to write the PR comment into")
+	fs.StringVar(&o.commit, "commit", "", "file to write the commit message into")
+	fs.StringVar(&o.reportPath, "report", "", "file to write a JSON report of the sync plan or result into")
+	fs.StringVar(&o.commentTemplatePath, "pr-comment-template", "", "template file used to render the PR comment instead of the default")
+	fs.StringVar(&o.commitTemplatePath, "commit-template", "", "template file used to render the commit message instead of the default")
+	fs.StringVar(&o.repositoryName, "repo", "", "repository name. Used for cover image URL")
+	fs.StringVar(&o.branch, "branch", "", "main branch name. Used for cover image URL")
+	fs.BoolVar(&o.force, "force", false, "update articles even if they were changed on dev.to since the last sync. With --pull, replace templates with the pulled body")
+	fs.BoolVar(&o.keepGoing, "keep-going", false, "continue synchronizing other articles when one fails and report all failures at the end")
+	fs.BoolVar(&o.seriesFooter, "series-footer", false, "add links to other posts in the series to the end of each article")
+	fs.BoolVar(&o.embedLinks, "embed-links", false, "publish links and URLs that are alone on a line as {% embed %} tags")
+}
+
+// run synchronizes the articles and writes the outputs. Outputs are written before returning errors
+// from synchronizing so they include articles that were synchronized
+func (o *syncOptions) run(ctx context.Context, c *client) error {
+	c.seriesFooter = o.seriesFooter
+	c.embedLinks = o.embedLinks
+	c.force = o.force
+	c.keepGoing = o.keepGoing
+	c.repositoryName = o.repositoryName
+	c.branch = o.branch
+
+	// articles are validated first so invalid metadata doesn't stop synchronizing after some articles are created.
+	// In keep-going mode, only the invalid articles are skipped
+	err := validateArticles(o.path)
+	var validationErr validationError
+	if errors.As(err, &validationErr) {
+		for _, p := range validationErr.problems {
+			c.actions.errorAt(p.File, p.Line, errors.New(p.Message))
+		}
+		if o.keepGoing {
+			c.invalidArticles, err = validationErr.byArticle(o.path)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	cfg, err := readConfig(o.path)
+	if err != nil {
+		return err
+	}
+
+	c.render, err = newArticleRenderer(o.path, cfg.Render)
+	if err != nil {
+		return err
+	}
+
+	// templates are read before synchronizing so a missing file doesn't prevent writing the commit after articles are created
+	prCommentTmpl, err := readTemplate(o.commentTemplatePath, commentTemplate)
+	if err != nil {
+		return fmt.Errorf("error reading PR comment template: %w", err)
+	}
+
+	commitTmpl, err := readTemplate(o.commitTemplatePath, commitTemplate)
+	if err != nil {
+		return fmt.Errorf("error reading commit template: %w", err)
+	}
+
+	var data commentData
+	if c.dryRun {
+		data.LintFindings, err = lintArticles(o.path, cfg.Lint)
+		if err != nil {
+			return fmt.Errorf("error linting articles: %w", err)
+		}
+
+		if cfg.Lint.enabled(lintBrokenLink) {
+			var resolver linkResolver
+			if cfg.Links.External {
+				resolver, err = newHTTPResolver(cfg.Links)
+				if err != nil {
+					return err
+				}
+			}
+
+			linkFindings, err := checkLinks(ctx, o.path, cfg.Links, resolver)
+			if err != nil {
+				return fmt.Errorf("error checking links: %w", err)
+			}
+			data.LintFindings = append(data.LintFindings, linkFindings...)
+		}
+
+		for _, f := range data.LintFindings {
+			c.actions.warningAt(f.File, f.Line, fmt.Sprintf("%s (%s)", f.Message, f.Rule))
+		}
+	}
+
+	start := time.Now()
+	syncErr := c.syncArticlesFromRootDirectory(ctx, o.path, &data)
+
+	if o.reportPath != "" {
+		err = writeReport(o.reportPath, c.dryRun, start, data, syncErr)
+		if err != nil {
+			return fmt.Errorf("error writing report: %w", err)
+		}
+	}
+
+	err = c.actions.writeSummary(prCommentTmpl, data)
+	if err != nil {
+		return fmt.Errorf("error writing GitHub Actions job summary: %w", err)
+	}
+
+	if o.prComment != "" {
+		err = renderTemplateToFile(o.prComment, prCommentTmpl, formatMarkdown, data)
+		if err != nil {
+			return fmt.Errorf("error writing PR comment: %w", err)
+		}
+	}
+
+	if o.commit != "" {
+		err = renderTemplateToFile(o.commit, commitTmpl, formatPlainText, data)
+		if err != nil {
+			return fmt.Errorf("error writing commit: %w", err)
+		}
+	}
+
+	if syncErr != nil {
+		return fmt.Errorf("error synchronizing directory: %w", syncErr)
+	}
+
+	return nil
+}
+
+func syncCommand(fs *flag.FlagSet) func([]string) error {
+	var apiOpts apiOptions
+	var syncOpts syncOptions
+	apiOpts.register(fs)
+	syncOpts.register(fs)
+
+	return func([]string) error {
+		return runSync(&apiOpts, &syncOpts, false, false)
+	}
+}
+
+func planCommand(fs *flag.FlagSet) func([]string) error {
+	var apiOpts apiOptions
+	var syncOpts syncOptions
+	var createImage bool
+	apiOpts.register(fs)
+	syncOpts.register(fs)
+	fs.BoolVar(&createImage, "create-image", false, "create gopher cover images for new articles")
+
+	return func([]string) error {
+		return runSync(&apiOpts, &syncOpts, true, createImage)
+	}
+}
+
+func runSync(apiOpts *apiOptions, syncOpts *syncOptions, dryRun, createImage bool) error {
+	c, done, err := apiOpts.newClient(dryRun, createImage)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	ctx, cancel := apiOpts.context()
+	defer cancel()
+
+	return syncOpts.run(ctx, c)
+}
+
+func initCommand(fs *flag.FlagSet) func([]string) error {
+	var apiOpts apiOptions
+	var path string
+	apiOpts.register(fs)
+	fs.StringVar(&path, "path", "./articles", "root path to create article directories in")
+
+	return func([]string) error {
+		c, done, err := apiOpts.newClient(false, false)
+		if err != nil {
+			return err
+		}
+		defer done()
+
+		ctx, cancel := apiOpts.context()
+		defer cancel()
+
+		err = c.init(ctx, path)
+		if err != nil {
+			return fmt.Errorf("error initializing: %w", err)
+		}
+		return nil
+	}
+}
+
+func pullCommand(fs *flag.FlagSet) func([]string) error {
+	var apiOpts apiOptions
+	var path string
+	var dryRun, seriesFooter, force, embedLinks bool
+	apiOpts.register(fs)
+	fs.StringVar(&path, "path", "./articles", "root path to scan for articles")
+	fs.BoolVar(&dryRun, "dry-run", false, "log the changes without writing them")
+	fs.BoolVar(&seriesFooter, "series-footer", false, "remove the series footer added by sync from the pulled body")
+	fs.BoolVar(&force, "force", false, "replace templates in article.md with the pulled body")
+	fs.BoolVar(&embedLinks, "embed-links", false, "change {% embed %} tags added by sync back to links in the pulled body")
+
+	return func([]string) error {
+		c, done, err := apiOpts.newClient(dryRun, false)
+		if err != nil {
+			return err
+		}
+		defer done()
+
+		ctx, cancel := apiOpts.context()
+		defer cancel()
+
+		return runPull(ctx, c, path, seriesFooter, force, embedLinks)
+	}
+}
+
+// runPull sets the pull options on the client and pulls the articles. It is shared by the pull command and
+// the legacy --pull flag so they behave the same
+func runPull(ctx context.Context, c *client, path string, seriesFooter, force, embedLinks bool) error {
+	c.seriesFooter = seriesFooter
+	c.force = force
+	c.embedLinks = embedLinks
+
+	var err error
+	c.render, err = loadArticleRenderer(path)
+	if err != nil {
+		return err
+	}
+
+	err = c.pull(ctx, path)
+	if err != nil {
+		return fmt.Errorf("error pulling articles: %w", err)
+	}
+	return nil
+}
+
+func statusCommand(fs *flag.FlagSet) func([]string) error {
+	var apiOpts apiOptions
+	var path, format string
+	var seriesFooter, embedLinks bool
+	apiOpts.register(fs)
+	fs.StringVar(&path, "path", "./articles", "root path to scan for articles")
+	fs.StringVar(&format, "format", "table", "output format: table or json")
+	fs.BoolVar(&seriesFooter, "series-footer", false, "include the series footer added by sync when comparing articles")
+	fs.BoolVar(&embedLinks, "embed-links", false, "embed standalone links like sync when comparing articles")
+
+	return func([]string) error {
+		write, ok := map[string]func(io.Writer, []articleStatus) error{
+			"table": writeStatusTable,
+			"json":  writeStatusJSON,
+		}[format]
+		if !ok {
+			return usageError{fmt.Sprintf("invalid format %q: use table or json", format)}
+		}
+
+		c, done, err := apiOpts.newClient(true, false)
+		if err != nil {
+			return err
+		}
+		defer done()
+		c.seriesFooter = seriesFooter
+		c.embedLinks = embedLinks
+
+		c.render, err = loadArticleRenderer(path)
+		if err != nil {
+			return err
+		}
+
+		// only warnings are logged, and to stderr, so the output can be parsed
+		c.logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+		c.retry.logger = c.logger
+
+		ctx, cancel := apiOpts.context()
+		defer cancel()
+
+		statuses, err := c.status(ctx, path)
+		if err != nil {
+			return fmt.Errorf("error getting status: %w", err)
+		}
+
+		return write(os.Stdout, statuses)
+	}
+}
+
+func validateCommand(fs *flag.FlagSet) func([]string) error {
+	var path string
+	var printSchema bool
+	fs.StringVar(&path, "path", "./articles", "root path to scan for articles")
+	fs.BoolVar(&printSchema, "print-schema", false, "print the JSON Schema for article.json instead of validating")
+
+	return func([]string) error {
+		if printSchema {
+			_, err := os.Stdout.Write(articleSchema)
+			return err
+		}
+
+		err := validateArticles(path)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("all articles are valid")
+		return nil
+	}
+}
+
+func newCommand(fs *flag.FlagSet) func([]string) error {
+	var path, description, tags, gopher, series, organization string
+	var cover bool
+	fs.StringVar(&path, "path", "./articles", "root path to create the article directory in")
+	fs.StringVar(&description, "description", "", "description of the article")
+	fs.StringVar(&tags, "tags", "", "comma-separated tags. Defaults to tags from "+configFileName)
+	fs.StringVar(&gopher, "gopher", "", "URL of the gopher image used for the cover image. Defaults to gopher from "+configFileName)
+	fs.StringVar(&series, "series", "", "name of the series the article is part of")
+	fs.StringVar(&organization, "organization", "", "username of the organization to publish the article under")
+	fs.BoolVar(&cover, "cover", false, "create the cover image from the gopher now instead of when the article is published")
+
+	return func(args []string) error {
+		if len(args) != 1 {
+			return usageError{"expected exactly one title"}
+		}
+
+		article := &Article{
+			Title:        args[0],
+			Description:  description,
+			Gopher:       gopher,
+			Series:       series,
+			Organization: organization,
+		}
+		for _, tag := range strings.Split(tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				article.Tags = append(article.Tags, tag)
+			}
+		}
+
+		dir, err := newArticle(path, article)
+		if err != nil {
+			return err
+		}
+		log.Printf("created %s", dir)
+
+		if !cover {
+			return nil
+		}
+
+		if article.Gopher == "" {
+			return fmt.Errorf("cannot create cover image without a gopher")
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		err = writeCoverImage(ctx, dir, article)
+		if err != nil {
+			return err
+		}
+		log.Printf("created %s", coverImagePath(dir))
+
+		return nil
+	}
+}
+
+func renderCommand(fs *flag.FlagSet) func([]string) error {
+	var path string
+	var seriesFooter, embedLinks bool
+	fs.StringVar(&path, "path", "./articles", "root path of the articles, used for the config, partials, and series")
+	fs.BoolVar(&seriesFooter, "series-footer", false, "add the series footer like sync")
+	fs.BoolVar(&embedLinks, "embed-links", false, "embed standalone links like sync")
+
+	return func(args []string) error {
+		if len(args) != 1 {
+			return usageError{"expected exactly one article directory"}
+		}
+		// the directory is cleaned so it matches the directories in the series index
+		dir := filepath.Clean(args[0])
+
+		renderer, err := loadArticleRenderer(path)
+		if err != nil {
+			return err
+		}
+		c := &client{seriesFooter: seriesFooter, embedLinks: embedLinks, render: renderer}
+
+		article, err := readArticleFile(dir)
+		if err != nil {
+			return fmt.Errorf("error reading article %s: %w", dir, err)
+		}
+
+		var series seriesIndex
+		if seriesFooter {
+			series, err = readSeriesIndex(path)
+			if err != nil {
+				return err
+			}
+		}
+
+		body, err := c.publishedBody(dir, article, series)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(os.Stdout, body)
+		return err
+	}
+}
+
+func coverCommand(fs *flag.FlagSet) func([]string) error {
+	var force bool
+	fs.BoolVar(&force, "force", false, "replace existing cover images")
+
+	return func(dirs []string) error {
+		if len(dirs) == 0 {
+			return usageError{"missing article directory"}
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		for _, dir := range dirs {
+			article, err := readArticleFile(dir)
+			if err != nil {
+				return fmt.Errorf("error reading article %s: %w", dir, err)
+			}
+
+			if article.Gopher == "" {
+				return fmt.Errorf("article %s does not have a gopher", dir)
+			}
+
+			_, err = os.Stat(coverImagePath(dir))
+			if err == nil && !force {
+				return fmt.Errorf("cover image already exists for %s: use --force to replace it", dir)
+			}
+
+			err = writeCoverImage(ctx, dir, article)
+			if err != nil {
+				return err
+			}
+			log.Printf("created %s", coverImagePath(dir))
+		}
+
+		return nil
+	}
+}
+
+// legacyCommand is the flag-only interface used before subcommands. It is kept for the GitHub Action
+var legacyCommand = command{
+	name:    "",
+	summary: "synchronize articles. Use --init, --pull, or --dry-run for the other modes or run \"article-sync help\" for commands",
+	setup: func(fs *flag.FlagSet) func([]string) error {
+		var apiOpts apiOptions
+		var syncOpts syncOptions
+		var dryRun, createImage, init, pull bool
+		apiOpts.register(fs)
+		syncOpts.register(fs)
+		fs.BoolVar(&dryRun, "dry-run", false, "dry-run to print which changes will be made without doing them")
+		fs.BoolVar(&createImage, "create-image", false, "create gopher cover image even if using dry-run")
+		fs.BoolVar(&init, "init", false, "download articles from profile and create directories")
+		fs.BoolVar(&pull, "pull", false, "overwrite local articles with changes made on dev.to")
+
+		return func([]string) error {
+			c, done, err := apiOpts.newClient(dryRun, createImage)
+			if err != nil {
+				return err
+			}
+			defer done()
+
+			ctx, cancel := apiOpts.context()
+			defer cancel()
+
+			switch {
+			case init:
+				err = c.init(ctx, syncOpts.path)
+				if err != nil {
+					return fmt.Errorf("error initializing: %w", err)
+				}
+				return nil
+			case pull:
+				return runPull(ctx, c, syncOpts.path, syncOpts.seriesFooter, syncOpts.force, syncOpts.embedLinks)
+			}
+
+			return syncOpts.run(ctx, c)
+		}
+	},
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/calvinmclean/article-sync/fakeforem"
+)
+
+func TestRunCommands(t *testing.T) {
+	t.Setenv("API_KEY", "")
+	t.Setenv("GITHUB_ACTIONS", "")
+
+	forem := fakeforem.New(testAPIKey, "tester")
+	server := forem.Start()
+	defer server.Close()
+
+	root := t.TempDir()
+	writeTestArticle(t, root, "my-article", &Article{Title: "My Article"}, "Hello")
+	comment := filepath.Join(t.TempDir(), "comment.md")
+	apiFlags := []string{"--url", server.URL, "--api-key", testAPIKey, "--path", root}
+
+	tests := []struct {
+		name             string
+		args             []string
+		expectedCode     int
+		expectedOutput   string
+		expectedArticles int
+	}{
+		{"Help", []string{"help"}, exitOK, "Commands:", 0},
+		{"CommandHelp", []string{"help", "sync"}, exitOK, "Usage: article-sync sync [flags]", 0},
+		{"UnknownCommand", []string{"publish"}, exitUsage, `unknown command "publish"`, 0},
+		{"UnknownFlag", []string{"sync", "--unknown"}, exitUsage, "flag provided but not defined: -unknown", 0},
+		{"UnexpectedArgument", []string{"sync", "articles"}, exitUsage, "unexpected arguments: articles", 0},
+		{"MissingAPIKey", []string{"sync", "--path", root}, exitUsage, "missing required argument --api-key", 0},
+		{"CoverWithoutDirectory", []string{"cover"}, exitUsage, "missing article directory", 0},
+		{"RenderWithoutDirectory", []string{"render", "--path", root}, exitUsage, "expected exactly one article directory", 0},
+		{"CoverWithoutGopher", []string{"cover", filepath.Join(root, "my-article")}, exitError, "article " + filepath.Join(root, "my-article") + " does not have a gopher", 0},
+		{"Plan", append([]string{"plan", "--pr-comment", comment}, apiFlags...), exitOK, "", 0},
+		{"LegacyDryRun", append([]string{"--dry-run"}, apiFlags...), exitOK, "", 0},
+		{"Sync", append([]string{"sync"}, apiFlags...), exitOK, "", 1},
+		{"LegacySync", apiFlags, exitOK, "", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			code := run(tt.args, &stderr)
+			if code != tt.expectedCode {
+				t.Fatalf("expected exit code %d but got %d: %s", tt.expectedCode, code, stderr.String())
+			}
+
+			if !strings.Contains(stderr.String(), tt.expectedOutput) {
+				t.Fatalf("expected output to contain %q but got: %s", tt.expectedOutput, stderr.String())
+			}
+
+			if len(forem.Articles()) != tt.expectedArticles {
+				t.Fatalf("expected %d articles but got %d", tt.expectedArticles, len(forem.Articles()))
+			}
+		})
+	}
+
+	data, err := os.ReadFile(comment)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), "- My Article") {
+		t.Fatalf("expected plan to include new article: %s", data)
+	}
+}
+
+func TestSyncOptionsInvalidArticle(t *testing.T) {
+	tests := []struct {
+		name             string
+		keepGoing        bool
+		expectedArticles int
+		expectedOutputs  bool
+	}{
+		{"FailFast", false, 0, false},
+		{"KeepGoing", true, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, forem := newFakeForemClient(t, false)
+
+			root := t.TempDir()
+			invalid := writeTestArticle(t, root, "a", &Article{Title: "A"}, "")
+			writeTestArticle(t, root, "b", &Article{Title: "B"}, "Hello")
+
+			out := t.TempDir()
+			opts := syncOptions{
+				path:       root,
+				prComment:  filepath.Join(out, "comment.md"),
+				reportPath: filepath.Join(out, "report.json"),
+				keepGoing:  tt.keepGoing,
+			}
+
+			err := opts.run(context.Background(), c)
+			if err == nil || !strings.Contains(err.Error(), "article body must not be empty") {
+				t.Fatalf("expected validation error but got: %v", err)
+			}
+
+			if len(forem.Articles()) != tt.expectedArticles {
+				t.Fatalf("expected %d articles but got %d", tt.expectedArticles, len(forem.Articles()))
+			}
+
+			comment, err := os.ReadFile(opts.prComment)
+			if !tt.expectedOutputs {
+				if err == nil {
+					t.Fatalf("expected no PR comment but got: %s", comment)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(string(comment), "- "+invalid+": invalid article") || !strings.Contains(string(comment), "- B") {
+				t.Fatalf("expected PR comment to have the invalid and the new article: %s", comment)
+			}
+
+			data, err := os.ReadFile(opts.reportPath)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var r report
+			err = json.Unmarshal(data, &r)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(r.Articles) != 2 || r.Articles[0].Action != syncActionError || r.Articles[1].Action != syncActionCreate {
+				t.Fatalf("unexpected report articles: %+v", r.Articles)
+			}
+		})
+	}
+}
+
+func TestPullCommands(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"Pull", []string{"pull", "--force"}},
+		{"LegacyPull", []string{"--pull", "--force"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("API_KEY", "")
+			t.Setenv("GITHUB_ACTIONS", "")
+
+			forem := fakeforem.New(testAPIKey, "tester")
+			server := forem.Start()
+			defer server.Close()
+
+			root := t.TempDir()
+			dir := writeTestArticle(t, root, "my-article", &Article{Title: "My Article"}, "By {{ .Vars.author }}")
+			err := os.WriteFile(filepath.Join(root, configFileName), []byte(`{"render": {"vars": {"author": "Calvin"}}}`), 0640)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			apiFlags := []string{"--url", server.URL, "--api-key", testAPIKey, "--path", root}
+
+			var stderr bytes.Buffer
+			code := run(append([]string{"sync"}, apiFlags...), &stderr)
+			if code != exitOK {
+				t.Fatalf("unexpected exit code %d: %s", code, stderr.String())
+			}
+
+			article, err := readArticleFile(dir)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			forem.EditArticle(article.ID, func(a *fakeforem.Article) {
+				a.BodyMarkdown = "By Calvin, edited on dev.to"
+			})
+
+			code = run(append(tt.args, apiFlags...), &stderr)
+			if code != exitOK {
+				t.Fatalf("unexpected exit code %d: %s", code, stderr.String())
+			}
+
+			body, err := os.ReadFile(filepath.Join(dir, "article.md"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(body) != "By Calvin, edited on dev.to" {
+				t.Fatalf("expected --force to replace the templates but got %q", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// configFileName is read from the root articles directory
+const configFileName = "article-sync.json"
+
+// config has defaults for the articles in a directory. Every field is optional
+type config struct {
+	// Tags and Gopher are used by the new command for articles that don't set them
+	Tags   []string `json:"tags,omitempty"`
+	Gopher string   `json:"gopher,omitempty"`
+
+	// ArticleTemplate is a template file, relative to the root directory, used for article.md by the new command
+	ArticleTemplate string `json:"article_template,omitempty"`
+
+	// Lint configures the markdown lint rules checked by plan
+	Lint lintConfig `json:"lint,omitempty"`
+
+	// Links configures the link check done by plan, which is disabled with the broken-link lint rule
+	Links linkConfig `json:"links,omitempty"`
+
+	// Render enables templates in article.md when it is set
+	Render *renderConfig `json:"render,omitempty"`
+}
+
+// readConfig reads the config from the root directory. A missing file is an empty config
+func readConfig(rootDir string) (config, error) {
+	var result config
+
+	data, err := os.ReadFile(filepath.Join(rootDir, configFileName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return result, nil
+	}
+	if err != nil {
+		return result, fmt.Errorf("error reading config: %w", err)
+	}
+
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return result, fmt.Errorf("error parsing config %s: %w", filepath.Join(rootDir, configFileName), err)
+	}
+
+	return result, nil
+}
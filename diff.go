@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	diffContextLines = 3
+	maxDiffLines     = 200
+
+	// maxDiffCells limits memory used for very large changes. Beyond this, the changed lines
+	// are shown as deleted and inserted instead of finding the minimal diff
+	maxDiffCells = 4_000_000
+)
+
+type diffOp byte
+
+const (
+	diffEqual  diffOp = ' '
+	diffDelete diffOp = '-'
+	diffInsert diffOp = '+'
+)
+
+type diffLine struct {
+	op   diffOp
+	text string
+}
+
+// unifiedDiff creates a line diff from a to b in the unified format. The result is truncated
+// to maxLines so huge changes don't overwhelm the PR comment. An empty string is returned if
+// there are no differences
+func unifiedDiff(fromName, toName, a, b string, maxLines int) string {
+	if a == b {
+		return ""
+	}
+
+	lines := diffLines(splitLines(a), splitLines(b))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+
+	written := 0
+	for _, h := range hunks(lines) {
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(h.aStart, h.aLen), hunkRange(h.bStart, h.bLen))
+		for i, l := range h.lines {
+			if written == maxLines {
+				fmt.Fprintf(&sb, "... diff truncated, %d more lines\n", h.remaining(i))
+				return sb.String()
+			}
+			sb.WriteByte(byte(l.op))
+			sb.WriteString(l.text)
+			sb.WriteByte('\n')
+			written++
+		}
+	}
+
+	return sb.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines finds the longest common subsequence of lines to create a minimal list of edits.
+// Common prefix and suffix are trimmed first since most edits only touch a small part of an article
+func diffLines(a, b []string) []diffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	result := make([]diffLine, 0, len(a)+len(b))
+	for _, l := range a[:prefix] {
+		result = append(result, diffLine{diffEqual, l})
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(midA)*len(midB) > maxDiffCells {
+		for _, l := range midA {
+			result = append(result, diffLine{diffDelete, l})
+		}
+		for _, l := range midB {
+			result = append(result, diffLine{diffInsert, l})
+		}
+		midA, midB = nil, nil
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of midA[i:] and midB[j:]
+	lcs := make([][]int, len(midA)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(midB)+1)
+	}
+	for i := len(midA) - 1; i >= 0; i-- {
+		for j := len(midB) - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(midA) || j < len(midB) {
+		switch {
+		case i < len(midA) && j < len(midB) && midA[i] == midB[j]:
+			result = append(result, diffLine{diffEqual, midA[i]})
+			i++
+			j++
+		case i < len(midA) && (j == len(midB) || lcs[i+1][j] >= lcs[i][j+1]):
+			result = append(result, diffLine{diffDelete, midA[i]})
+			i++
+		default:
+			result = append(result, diffLine{diffInsert, midB[j]})
+			j++
+		}
+	}
+
+	for _, l := range a[len(a)-suffix:] {
+		result = append(result, diffLine{diffEqual, l})
+	}
+
+	return result
+}
+
+type hunk struct {
+	aStart, aLen int
+	bStart, bLen int
+	lines        []diffLine
+
+	// following is the number of lines in later hunks, used to report truncation
+	following int
+}
+
+func (h hunk) remaining(i int) int {
+	return len(h.lines) - i + h.following
+}
+
+// hunks groups the changed lines with diffContextLines of surrounding context. Changes that
+// are close enough for their context to overlap are combined into one hunk
+func hunks(lines []diffLine) []hunk {
+	// aLines[i] and bLines[i] are the number of lines from each side before lines[i]
+	aLines := make([]int, len(lines)+1)
+	bLines := make([]int, len(lines)+1)
+	var changes []int
+	for i, l := range lines {
+		aLines[i+1], bLines[i+1] = aLines[i], bLines[i]
+		if l.op != diffInsert {
+			aLines[i+1]++
+		}
+		if l.op != diffDelete {
+			bLines[i+1]++
+		}
+		if l.op != diffEqual {
+			changes = append(changes, i)
+		}
+	}
+
+	var result []hunk
+	for len(changes) > 0 {
+		last := 0
+		for last+1 < len(changes) && changes[last+1]-changes[last]-1 <= 2*diffContextLines {
+			last++
+		}
+
+		start := max(changes[0]-diffContextLines, 0)
+		end := min(changes[last]+diffContextLines+1, len(lines))
+		result = append(result, hunk{
+			aStart: aLines[start] + 1,
+			aLen:   aLines[end] - aLines[start],
+			bStart: bLines[start] + 1,
+			bLen:   bLines[end] - bLines[start],
+			lines:  lines[start:end],
+		})
+
+		changes = changes[last+1:]
+	}
+
+	for i := len(result) - 2; i >= 0; i-- {
+		result[i].following = len(result[i+1].lines) + result[i+1].following
+	}
+
+	return result
+}
+
+func hunkRange(start, length int) string {
+	if length == 0 {
+		start--
+	}
+	if length == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, length)
+}
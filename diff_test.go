@@ -0,0 +1,102 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		maxLines int
+		expected string
+	}{
+		{
+			"NoChanges",
+			"line 1\nline 2\n",
+			"line 1\nline 2\n",
+			maxDiffLines,
+			"",
+		},
+		{
+			"ChangedLine",
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			"1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			maxDiffLines,
+			`--- a
++++ b
+@@ -2,7 +2,7 @@
+ 2
+ 3
+ 4
+-5
++five
+ 6
+ 7
+ 8
+`,
+		},
+		{
+			"SeparateHunks",
+			"1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			"one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			maxDiffLines,
+			`--- a
++++ b
+@@ -1,4 +1,4 @@
+-1
++one
+ 2
+ 3
+ 4
+@@ -7,4 +7,4 @@
+ 7
+ 8
+ 9
+-10
++ten
+`,
+		},
+		{
+			"AddedToEmpty",
+			"",
+			"new\n",
+			maxDiffLines,
+			`--- a
++++ b
+@@ -0,0 +1 @@
++new
+`,
+		},
+		{
+			"Truncated",
+			"1\n2\n3\n",
+			"one\ntwo\nthree\n",
+			2,
+			`--- a
++++ b
+@@ -1,3 +1,3 @@
+-1
+-2
+... diff truncated, 4 more lines
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := unifiedDiff("a", "b", tt.a, tt.b, tt.maxLines)
+			if result != tt.expected {
+				t.Fatalf("unexpected result:\n%s", result)
+			}
+		})
+	}
+}
+
+func TestCodeBlock(t *testing.T) {
+	result := string(codeBlock("diff", "+```go\n+code\n+```\n"))
+	if !strings.HasPrefix(result, "````diff\n") || !strings.HasSuffix(result, "\n````") {
+		t.Fatalf("unexpected result: %s", result)
+	}
+}
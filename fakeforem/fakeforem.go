@@ -0,0 +1,587 @@
+// Package fakeforem is an in-memory implementation of the Forem article API used by dev.to. It is
+// used to test synchronization end to end without a dev.to account and can also be run with
+// cmd/fakeforem to rehearse a sync with the CLI
+package fakeforem
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Article is the state of an article stored by the Server
+type Article struct {
+	ID             int
+	Title          string
+	Description    string
+	BodyMarkdown   string
+	Tags           []string
+	Published      bool
+	Series         string
+	CanonicalURL   string
+	MainImage      string
+	OrganizationID int
+	Username       string
+	Slug           string
+	CreatedAt      time.Time
+	EditedAt       *time.Time
+}
+
+// Organization is an organization that articles can be published under
+type Organization struct {
+	ID       int
+	Username string
+	Name     string
+}
+
+// Fault makes matching requests fail instead of being handled. Method and Path are matched exactly
+// and match every request if they are empty
+type Fault struct {
+	Method string
+	Path   string
+
+	Status     int
+	RetryAfter string
+	Body       string
+
+	// Times is the number of requests that fail before the fault is removed. If it is 0, every
+	// matching request fails until ClearFaults is called
+	Times int
+}
+
+// Server handles requests to the article and organization endpoints from api/api.json
+type Server struct {
+	// APIKey is required in the api-key header of authenticated requests if it is set
+	APIKey string
+
+	// Username is the author of articles created by the API key
+	Username string
+
+	mu            sync.Mutex
+	articles      map[int]*Article
+	organizations map[string]*Organization
+	faults        []*Fault
+	requests      []string
+	nextID        int
+}
+
+// New creates a Server with no articles
+func New(apiKey, username string) *Server {
+	return &Server{
+		APIKey:        apiKey,
+		Username:      username,
+		articles:      map[int]*Article{},
+		organizations: map[string]*Organization{},
+		nextID:        1,
+	}
+}
+
+// Start runs the Server using httptest. The caller must close the returned server
+func (s *Server) Start() *httptest.Server {
+	return httptest.NewServer(s)
+}
+
+// AddArticle stores an article and returns its ID. If the ID is 0, the next available ID is used
+func (s *Server) AddArticle(a Article) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if a.ID == 0 {
+		a.ID = s.nextID
+	}
+	s.nextID = max(s.nextID, a.ID+1)
+
+	if a.Username == "" {
+		a.Username = s.Username
+	}
+	if a.Slug == "" {
+		a.Slug = slugify(a.Title, a.ID)
+	}
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now().UTC()
+	}
+
+	s.articles[a.ID] = &a
+	return a.ID
+}
+
+// Article returns a copy of the stored article
+func (s *Server) Article(id int) (Article, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.articles[id]
+	if !ok {
+		return Article{}, false
+	}
+	return *a, true
+}
+
+// Articles returns copies of every stored article ordered by ID
+func (s *Server) Articles() []Article {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sortedArticles(func(*Article) bool { return true })
+}
+
+// EditArticle changes a stored article like an edit made in the dev.to editor
+func (s *Server) EditArticle(id int, edit func(*Article)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.articles[id]
+	if !ok {
+		return false
+	}
+
+	edit(a)
+	now := time.Now().UTC()
+	a.EditedAt = &now
+	return true
+}
+
+// AddOrganization stores an organization
+func (s *Server) AddOrganization(org Organization) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.organizations[org.Username] = &org
+}
+
+// InjectFault adds a fault that is checked before handling each request
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all faults
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// Requests returns every request received as "METHOD /path", including ones that failed from faults
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.requests)
+}
+
+var (
+	articlePath         = regexp.MustCompile(`^/api/articles/(\d+)$`)
+	unpublishPath       = regexp.MustCompile(`^/api/articles/(\d+)/unpublish$`)
+	articleBySlugPath   = regexp.MustCompile(`^/api/articles/([^/]+)/([^/]+)$`)
+	organizationPath    = regexp.MustCompile(`^/api/organizations/([^/]+)$`)
+	organizationArticle = regexp.MustCompile(`^/api/organizations/([^/]+)/articles$`)
+)
+
+// ServeHTTP routes requests to the handler for each endpoint
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+
+	if s.applyFault(w, r) {
+		return
+	}
+
+	path := r.URL.Path
+	switch {
+	case path == "/api/articles" && r.Method == http.MethodGet:
+		s.listArticles(w, r, false, func(a *Article) bool { return a.Published })
+	case path == "/api/articles" && r.Method == http.MethodPost:
+		s.authenticated(w, r, s.createArticle)
+	case path == "/api/articles/me" || path == "/api/articles/me/published":
+		s.authenticated(w, r, func(w http.ResponseWriter, r *http.Request) {
+			s.listArticles(w, r, true, func(a *Article) bool { return a.Username == s.Username && a.Published })
+		})
+	case path == "/api/articles/me/unpublished":
+		s.authenticated(w, r, func(w http.ResponseWriter, r *http.Request) {
+			s.listArticles(w, r, true, func(a *Article) bool { return a.Username == s.Username && !a.Published })
+		})
+	case path == "/api/articles/me/all":
+		s.authenticated(w, r, func(w http.ResponseWriter, r *http.Request) {
+			s.listArticles(w, r, true, func(a *Article) bool { return a.Username == s.Username })
+		})
+	case articlePath.MatchString(path) && r.Method == http.MethodGet:
+		s.getArticle(w, r, pathID(articlePath, path))
+	case articlePath.MatchString(path) && r.Method == http.MethodPut:
+		s.authenticated(w, r, func(w http.ResponseWriter, r *http.Request) {
+			s.updateArticle(w, r, pathID(articlePath, path))
+		})
+	case unpublishPath.MatchString(path) && r.Method == http.MethodPut:
+		s.authenticated(w, r, func(w http.ResponseWriter, r *http.Request) {
+			s.unpublishArticle(w, pathID(unpublishPath, path))
+		})
+	case organizationArticle.MatchString(path) && r.Method == http.MethodGet:
+		s.listOrganizationArticles(w, r, organizationArticle.FindStringSubmatch(path)[1])
+	case organizationPath.MatchString(path) && r.Method == http.MethodGet:
+		s.getOrganization(w, r, organizationPath.FindStringSubmatch(path)[1])
+	case articleBySlugPath.MatchString(path) && r.Method == http.MethodGet:
+		match := articleBySlugPath.FindStringSubmatch(path)
+		s.getArticleBySlug(w, r, match[1], match[2])
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (s *Server) applyFault(w http.ResponseWriter, r *http.Request) bool {
+	for i, f := range s.faults {
+		if (f.Method != "" && f.Method != r.Method) || (f.Path != "" && f.Path != r.URL.Path) {
+			continue
+		}
+
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = slices.Delete(s.faults, i, i+1)
+			}
+		}
+
+		if f.RetryAfter != "" {
+			w.Header().Set("Retry-After", f.RetryAfter)
+		}
+
+		body := f.Body
+		if body == "" {
+			body = http.StatusText(f.Status)
+		}
+		writeError(w, f.Status, body)
+		return true
+	}
+
+	return false
+}
+
+func (s *Server) authenticated(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if s.APIKey != "" && r.Header.Get("api-key") != s.APIKey {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	next(w, r)
+}
+
+// articleRequest is the body used to create and update articles
+type articleRequest struct {
+	Article struct {
+		Title          *string   `json:"title"`
+		Description    *string   `json:"description"`
+		BodyMarkdown   *string   `json:"body_markdown"`
+		Tags           *[]string `json:"tags"`
+		Published      *bool     `json:"published"`
+		Series         *string   `json:"series"`
+		CanonicalURL   *string   `json:"canonical_url"`
+		MainImage      *string   `json:"main_image"`
+		OrganizationID *int      `json:"organization_id"`
+	} `json:"article"`
+}
+
+func (req articleRequest) apply(a *Article) {
+	if req.Article.Title != nil {
+		a.Title = *req.Article.Title
+	}
+	if req.Article.Description != nil {
+		a.Description = *req.Article.Description
+	}
+	if req.Article.BodyMarkdown != nil {
+		a.BodyMarkdown = *req.Article.BodyMarkdown
+	}
+	if req.Article.Tags != nil {
+		a.Tags = *req.Article.Tags
+	}
+	if req.Article.Published != nil {
+		a.Published = *req.Article.Published
+	}
+	if req.Article.Series != nil {
+		a.Series = *req.Article.Series
+	}
+	if req.Article.CanonicalURL != nil {
+		a.CanonicalURL = *req.Article.CanonicalURL
+	}
+	if req.Article.MainImage != nil {
+		a.MainImage = *req.Article.MainImage
+	}
+	if req.Article.OrganizationID != nil {
+		a.OrganizationID = *req.Article.OrganizationID
+	}
+}
+
+func (s *Server) createArticle(w http.ResponseWriter, r *http.Request) {
+	var req articleRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Article.Title == nil || *req.Article.Title == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Title can't be blank")
+		return
+	}
+
+	if req.Article.OrganizationID != nil && *req.Article.OrganizationID != 0 && s.organizationByID(*req.Article.OrganizationID) == nil {
+		writeError(w, http.StatusUnprocessableEntity, "Organization not found")
+		return
+	}
+
+	a := &Article{
+		ID:        s.nextID,
+		Username:  s.Username,
+		CreatedAt: time.Now().UTC(),
+	}
+	s.nextID++
+	req.apply(a)
+	a.Slug = slugify(a.Title, a.ID)
+	s.articles[a.ID] = a
+
+	writeJSON(w, http.StatusCreated, s.articleJSON(r, a))
+}
+
+func (s *Server) updateArticle(w http.ResponseWriter, r *http.Request, id int) {
+	a, ok := s.articles[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	if a.Username != s.Username {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req articleRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	req.apply(a)
+	now := time.Now().UTC()
+	a.EditedAt = &now
+
+	writeJSON(w, http.StatusOK, s.articleJSON(r, a))
+}
+
+func (s *Server) unpublishArticle(w http.ResponseWriter, id int) {
+	a, ok := s.articles[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	a.Published = false
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getArticle only returns published articles, like dev.to
+func (s *Server) getArticle(w http.ResponseWriter, r *http.Request, id int) {
+	a, ok := s.articles[id]
+	if !ok || !a.Published {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.articleJSON(r, a))
+}
+
+func (s *Server) getArticleBySlug(w http.ResponseWriter, r *http.Request, username, slug string) {
+	for _, a := range s.articles {
+		if a.Username == username && a.Slug == slug && a.Published {
+			writeJSON(w, http.StatusOK, s.articleJSON(r, a))
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "not found")
+}
+
+func (s *Server) getOrganization(w http.ResponseWriter, r *http.Request, username string) {
+	org, ok := s.organizations[username]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"type_of":  "organization",
+		"id":       org.ID,
+		"username": org.Username,
+		"name":     org.Name,
+		"url":      baseURL(r) + "/" + org.Username,
+	})
+}
+
+func (s *Server) listOrganizationArticles(w http.ResponseWriter, r *http.Request, username string) {
+	org, ok := s.organizations[username]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	s.listArticles(w, r, false, func(a *Article) bool {
+		return a.OrganizationID == org.ID && a.Published
+	})
+}
+
+// listArticles writes a page of articles that match the filter. Pages default to 30 articles like dev.to.
+// Like dev.to, listings of the user's own articles include the markdown body
+func (s *Server) listArticles(w http.ResponseWriter, r *http.Request, withBody bool, filter func(*Article) bool) {
+	page, perPage := 1, 30
+	if v, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && v > 0 {
+		page = v
+	}
+	if v, err := strconv.Atoi(r.URL.Query().Get("per_page")); err == nil && v > 0 {
+		perPage = min(v, 1000)
+	}
+
+	articles := s.sortedArticles(filter)
+	start := min((page-1)*perPage, len(articles))
+	end := min(start+perPage, len(articles))
+
+	result := []map[string]any{}
+	for _, a := range articles[start:end] {
+		article := s.articleIndexJSON(r, &a)
+		if withBody {
+			article["body_markdown"] = a.BodyMarkdown
+		}
+		result = append(result, article)
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) sortedArticles(filter func(*Article) bool) []Article {
+	var result []Article
+	for _, a := range s.articles {
+		if filter(a) {
+			result = append(result, *a)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b Article) int {
+		return a.ID - b.ID
+	})
+
+	return result
+}
+
+func (s *Server) organizationByID(id int) *Organization {
+	for _, org := range s.organizations {
+		if org.ID == id {
+			return org
+		}
+	}
+	return nil
+}
+
+// articleJSON is the response for a single article, where tags is a list and tag_list is a string
+func (s *Server) articleJSON(r *http.Request, a *Article) map[string]any {
+	result := s.commonArticleJSON(r, a)
+	result["body_markdown"] = a.BodyMarkdown
+	result["tags"] = tagsOrEmpty(a.Tags)
+	result["tag_list"] = strings.Join(a.Tags, ", ")
+	return result
+}
+
+// articleIndexJSON is the response for an article in a list, where tag_list is a list and tags is a string
+func (s *Server) articleIndexJSON(r *http.Request, a *Article) map[string]any {
+	result := s.commonArticleJSON(r, a)
+	result["tag_list"] = tagsOrEmpty(a.Tags)
+	result["tags"] = strings.Join(a.Tags, ", ")
+	return result
+}
+
+func (s *Server) commonArticleJSON(r *http.Request, a *Article) map[string]any {
+	path := "/" + a.Username + "/" + a.Slug
+	result := map[string]any{
+		"type_of":       "article",
+		"id":            a.ID,
+		"title":         a.Title,
+		"description":   a.Description,
+		"slug":          a.Slug,
+		"path":          path,
+		"url":           baseURL(r) + path,
+		"canonical_url": a.CanonicalURL,
+		"cover_image":   nullIfEmpty(a.MainImage),
+		"published":     a.Published,
+		"created_at":    a.CreatedAt,
+		"edited_at":     a.EditedAt,
+		"user": map[string]any{
+			"username": a.Username,
+		},
+	}
+
+	if a.Published {
+		result["published_at"] = a.CreatedAt
+		result["published_timestamp"] = a.CreatedAt
+	}
+
+	if org := s.organizationByID(a.OrganizationID); org != nil {
+		result["organization"] = map[string]any{
+			"name":     org.Name,
+			"username": org.Username,
+			"slug":     org.Username,
+		}
+	}
+
+	return result
+}
+
+func baseURL(r *http.Request) string {
+	return "http://" + r.Host
+}
+
+var nonSlugCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify creates a slug from the title with a suffix to make it unique, like dev.to
+func slugify(title string, id int) string {
+	slug := strings.Trim(nonSlugCharacters.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	return fmt.Sprintf("%s-%s", slug, strconv.FormatInt(int64(id), 36))
+}
+
+func tagsOrEmpty(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+func nullIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func pathID(re *regexp.Regexp, path string) int {
+	id, _ := strconv.Atoi(re.FindStringSubmatch(path)[1])
+	return id
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{
+		"error":  message,
+		"status": status,
+	})
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// githubActions writes job summaries and workflow command annotations when running in GitHub Actions
+type githubActions struct {
+	out         io.Writer
+	summaryPath string
+}
+
+// newGitHubActions returns nil when not running in GitHub Actions. All methods are safe to use on nil
+func newGitHubActions() *githubActions {
+	if os.Getenv("GITHUB_ACTIONS") != "true" {
+		return nil
+	}
+
+	return &githubActions{
+		out:         os.Stdout,
+		summaryPath: os.Getenv("GITHUB_STEP_SUMMARY"),
+	}
+}
+
+// annotationMessageEscaper and annotationPropertyEscaper escape workflow command values:
+// https://github.com/actions/toolkit/blob/main/packages/core/src/command.ts
+var (
+	annotationMessageEscaper  = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	annotationPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+// annotate writes an annotation for the file, or the whole run if file is empty. The line is only used if it is positive
+func (g *githubActions) annotate(level, file string, line int, message string) {
+	if g == nil {
+		return
+	}
+
+	switch {
+	case file == "":
+		fmt.Fprintf(g.out, "::%s::%s\n", level, annotationMessageEscaper.Replace(message))
+	case line > 0:
+		fmt.Fprintf(g.out, "::%s file=%s,line=%d::%s\n", level, annotationPropertyEscaper.Replace(file), line, annotationMessageEscaper.Replace(message))
+	default:
+		fmt.Fprintf(g.out, "::%s file=%s::%s\n", level, annotationPropertyEscaper.Replace(file), annotationMessageEscaper.Replace(message))
+	}
+}
+
+func (g *githubActions) error(file string, err error) {
+	g.annotate("error", file, 0, err.Error())
+}
+
+func (g *githubActions) errorAt(file string, line int, err error) {
+	g.annotate("error", file, line, err.Error())
+}
+
+func (g *githubActions) warning(file, message string) {
+	g.annotate("warning", file, 0, message)
+}
+
+func (g *githubActions) warningAt(file string, line int, message string) {
+	g.annotate("warning", file, line, message)
+}
+
+// writeSummary renders the template and appends it to the job summary
+func (g *githubActions) writeSummary(tmpl string, data commentData) error {
+	if g == nil || g.summaryPath == "" {
+		return nil
+	}
+
+	file, err := os.OpenFile(g.summaryPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening job summary file: %w", err)
+	}
+	defer file.Close()
+
+	err = renderTemplate(tmpl, formatMarkdown, data, file)
+	if err != nil {
+		return fmt.Errorf("error writing job summary: %w", err)
+	}
+
+	_, err = file.WriteString("\n")
+	if err != nil {
+		return fmt.Errorf("error writing job summary: %w", err)
+	}
+
+	return nil
+}
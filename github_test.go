@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGitHubActionsAnnotations(t *testing.T) {
+	var out bytes.Buffer
+	g := &githubActions{out: &out}
+
+	g.error("articles/a,b/article.json", errors.New("error parsing article details:\n100% broken"))
+	g.warning("", "no file")
+	g.warningAt("articles/a/article.md", 3, "image has no alt text")
+	g.errorAt("articles/a/article.md", 5, errors.New(`unknown liquid tag "youtub"`))
+
+	expected := "::error file=articles/a%2Cb/article.json::error parsing article details:%0A100%25 broken\n::warning::no file\n" +
+		"::warning file=articles/a/article.md,line=3::image has no alt text\n" +
+		"::error file=articles/a/article.md,line=5::unknown liquid tag \"youtub\"\n"
+	if out.String() != expected {
+		t.Fatalf("unexpected result: %s", out.String())
+	}
+}
+
+func TestGitHubActionsNil(t *testing.T) {
+	var g *githubActions
+	g.error("file", errors.New("error"))
+
+	err := g.writeSummary(commentTemplate, commentData{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGitHubActionsWriteSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.md")
+	err := os.WriteFile(path, []byte("existing\n"), 0644)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	g := &githubActions{summaryPath: path}
+	err = g.writeSummary(`{{ len .NewArticles }} new`, commentData{NewArticles: []*Article{{}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(result) != "existing\n1 new\n" {
+		t.Fatalf("unexpected result: %s", result)
+	}
+}
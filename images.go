@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"image"
 	"image/color"
 	"io"
 	"net/http"
+	"path/filepath"
+	"time"
 
 	"github.com/fogleman/gg"
 	"github.com/golang/freetype"
@@ -15,8 +18,31 @@ import (
 	"golang.org/x/image/font/gofont/goregular"
 )
 
-func createCoverImage(gopherURL, title string) (image.Image, error) {
-	gopher, err := downloadAndResizeImage(gopherURL)
+// imageClient downloads gophers. The timeout stops a URL that never responds from blocking the cover command
+// or a sync, since their contexts may not have a deadline
+var imageClient = &http.Client{Timeout: 30 * time.Second}
+
+func coverImagePath(dir string) string {
+	return filepath.Join(dir, "cover_image.png")
+}
+
+// writeCoverImage creates the cover image from the article's gopher and saves it in the article directory
+func writeCoverImage(ctx context.Context, dir string, article *Article) error {
+	coverImg, err := createCoverImage(ctx, article.Gopher, article.Title)
+	if err != nil {
+		return fmt.Errorf("error creating cover image: %w", err)
+	}
+
+	err = gg.SavePNG(coverImagePath(dir), coverImg)
+	if err != nil {
+		return fmt.Errorf("error saving image: %w", err)
+	}
+
+	return nil
+}
+
+func createCoverImage(ctx context.Context, gopherURL, title string) (image.Image, error) {
+	gopher, err := downloadAndResizeImage(ctx, gopherURL)
 	if err != nil {
 		return nil, fmt.Errorf("error getting gopher image: %w", err)
 	}
@@ -34,13 +60,22 @@ func createCoverImage(gopherURL, title string) (image.Image, error) {
 	return combined, nil
 }
 
-func downloadAndResizeImage(url string) (image.Image, error) {
-	resp, err := http.Get(url)
+func downloadAndResizeImage(ctx context.Context, url string) (image.Image, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request to get image: %w", err)
+	}
+
+	resp, err := imageClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("error making request to get image: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status getting image: %d", resp.StatusCode)
+	}
+
 	img, err := resizeImage(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("error resizing image: %w", err)
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDownloadAndResizeImageTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := imageClient
+	imageClient = &http.Client{Timeout: 50 * time.Millisecond}
+	defer func() { imageClient = client }()
+
+	_, err := downloadAndResizeImage(context.Background(), server.URL)
+	if err == nil || !strings.Contains(err.Error(), "Client.Timeout exceeded") {
+		t.Fatalf("expected timeout error but got: %v", err)
+	}
+}
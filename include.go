@@ -0,0 +1,292 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// includePattern matches an include directive, which is an HTML comment on its own line so it is hidden
+// when the markdown is viewed on GitHub:
+//
+//	<!-- include ../../examples/server/main.go region=handler -->
+var includePattern = regexp.MustCompile(`^(\s*)<!--\s*include\s+(.*?)\s*-->\s*$`)
+
+// includeDirective includes part of a source file, relative to the article directory, as a code block.
+// It has the whole file, a range of lines, or the lines between region markers
+type includeDirective struct {
+	Line   int
+	Text   string
+	Indent string
+
+	Path       string
+	Start, End int
+	Region     string
+	Lang       string
+
+	err error
+}
+
+// findIncludes returns the include directives outside of code blocks. Directives that can't be parsed
+// have an error, which is returned when reading them
+func findIncludes(body string) []includeDirective {
+	var result []includeDirective
+
+	var fence codeFence
+	for i, line := range splitLines(body) {
+		if fence.code(line) {
+			continue
+		}
+
+		match := includePattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		d := includeDirective{Line: i + 1, Text: line, Indent: match[1]}
+		d.err = d.parse(match[2])
+		result = append(result, d)
+	}
+
+	return result
+}
+
+// parse reads the path followed by the options: lines=START-END, lines=START-, lines=LINE, region=NAME,
+// and lang=LANGUAGE. The language defaults to the file extension
+func (d *includeDirective) parse(args string) error {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return errors.New("include needs a file path")
+	}
+
+	d.Path = fields[0]
+	d.Lang = strings.TrimPrefix(filepath.Ext(d.Path), ".")
+
+	for _, field := range fields[1:] {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok || value == "" {
+			return fmt.Errorf("invalid include option %q: use key=value", field)
+		}
+
+		switch key {
+		case "lines":
+			start, end, isRange := strings.Cut(value, "-")
+			var err error
+			d.Start, err = strconv.Atoi(start)
+			if err != nil || d.Start < 1 {
+				return fmt.Errorf("invalid include lines %q: lines start at 1", value)
+			}
+			switch {
+			case !isRange:
+				d.End = d.Start
+			case end == "":
+				d.End = -1
+			default:
+				d.End, err = strconv.Atoi(end)
+				if err != nil || d.End < d.Start {
+					return fmt.Errorf("invalid include lines %q: the end must be a line after the start", value)
+				}
+			}
+		case "region":
+			d.Region = value
+		case "lang":
+			d.Lang = value
+		default:
+			return fmt.Errorf("unknown include option %q", key)
+		}
+	}
+
+	if d.Start != 0 && d.Region != "" {
+		return errors.New("include can't use both lines and region")
+	}
+
+	return nil
+}
+
+// snippet reads the included lines. Indentation shared by every line is removed
+func (d includeDirective) snippet(dir string) (string, error) {
+	if d.err != nil {
+		return "", d.err
+	}
+
+	path, err := filepath.Abs(filepath.Join(dir, filepath.FromSlash(d.Path)))
+	if err != nil {
+		return "", fmt.Errorf("error reading included file: %w", err)
+	}
+	root, err := includeRoot(dir)
+	if err != nil {
+		return "", err
+	}
+	if !isWithin(root, path) {
+		return "", fmt.Errorf("included file %s is outside of %s", d.Path, root)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading included file: %w", err)
+	}
+	lines := splitLines(string(data))
+
+	switch {
+	case d.Region != "":
+		lines, err = regionLines(lines, d.Region)
+		if err != nil {
+			return "", err
+		}
+	case d.Start != 0:
+		end := d.End
+		if end == -1 {
+			end = len(lines)
+		}
+		if end > len(lines) {
+			return "", fmt.Errorf("included lines %d-%d are past the end of %s, which has %d lines", d.Start, end, d.Path, len(lines))
+		}
+		lines = lines[d.Start-1 : end]
+	}
+
+	return strings.Join(dedent(lines), "\n"), nil
+}
+
+// includeRoot is the directory that included files must be in: the root of the git repository that has the
+// article, or the working directory if it isn't in a repository
+func includeRoot(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("error finding repository root: %w", err)
+	}
+
+	for d := abs; ; d = filepath.Dir(d) {
+		_, err := os.Stat(filepath.Join(d, ".git"))
+		if err == nil {
+			return d, nil
+		}
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+
+	return os.Getwd()
+}
+
+// regionLines returns the lines between comments like "// region NAME" and "// endregion NAME". Markers
+// of other regions inside it are removed
+func regionLines(lines []string, name string) ([]string, error) {
+	start := regexp.MustCompile(`\bregion\s+` + regexp.QuoteMeta(name) + `\s*(\*/|-->)?\s*$`)
+	end := regexp.MustCompile(`\bendregion\s+` + regexp.QuoteMeta(name) + `\s*(\*/|-->)?\s*$`)
+	anyMarker := regexp.MustCompile(`\b(end)?region\s+\S+\s*(\*/|-->)?\s*$`)
+
+	var result []string
+	inRegion, found := false, false
+	for _, line := range lines {
+		switch {
+		case !inRegion && start.MatchString(line):
+			inRegion, found = true, true
+		case inRegion && end.MatchString(line):
+			return result, nil
+		case inRegion && !anyMarker.MatchString(line):
+			result = append(result, line)
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("region %q not found", name)
+	}
+	return nil, fmt.Errorf("region %q is never closed with endregion", name)
+}
+
+// dedent removes leading whitespace shared by every non-blank line
+func dedent(lines []string) []string {
+	prefix := ""
+	first := true
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if first {
+			prefix, first = indent, false
+			continue
+		}
+		for !strings.HasPrefix(indent, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		result[i] = strings.TrimPrefix(line, prefix)
+	}
+	return result
+}
+
+// codeBlock fences the snippet with enough backticks that fences in the snippet don't end it
+func (d includeDirective) codeBlock(snippet string) string {
+	fence := "```"
+	for strings.Contains(snippet, fence) {
+		fence += "`"
+	}
+
+	lines := splitLines(fmt.Sprintf("%s%s\n%s\n%s", fence, d.Lang, snippet, fence))
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = d.Indent + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// expandedInclude is the code block that replaced an include directive
+type expandedInclude struct {
+	directive string
+	block     string
+}
+
+// expandIncludes replaces each include directive with a code block of the snippet. The expansions are
+// returned so they can be changed back to directives by collapseIncludes
+func expandIncludes(dir, body string) (string, []expandedInclude, error) {
+	includes := findIncludes(body)
+	if len(includes) == 0 {
+		return body, nil, nil
+	}
+
+	var expanded []expandedInclude
+	lines := strings.SplitAfter(body, "\n")
+	for _, d := range includes {
+		snippet, err := d.snippet(dir)
+		if err != nil {
+			return "", nil, fmt.Errorf("error including %s on line %d: %w", d.Path, d.Line, err)
+		}
+
+		block := d.codeBlock(snippet)
+		expanded = append(expanded, expandedInclude{d.Text, block})
+
+		line := lines[d.Line-1]
+		lines[d.Line-1] = block + line[len(strings.TrimRight(line, "\r\n")):]
+	}
+
+	return strings.Join(lines, ""), expanded, nil
+}
+
+// collapseIncludes changes code blocks that still match their expansion back to include directives
+func collapseIncludes(body string, expanded []expandedInclude) string {
+	for _, e := range expanded {
+		body = strings.Replace(body, e.block, e.directive, 1)
+	}
+	return body
+}
+
+// validateIncludes checks that every include directive can be read
+func validateIncludes(dir, body string) []validationProblem {
+	var problems []validationProblem
+	for _, d := range findIncludes(body) {
+		_, err := d.snippet(dir)
+		if err != nil {
+			problems = append(problems, validationProblem{File: filepath.Join(dir, "article.md"), Line: d.Line, Message: err.Error()})
+		}
+	}
+	return problems
+}
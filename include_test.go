@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testIncludeSource = `package main
+
+import "fmt"
+
+func main() {
+	// region greeting
+	name := "World"
+	// region inner
+	fmt.Printf("Hello, %s\n", name)
+	// endregion inner
+	// endregion greeting
+}
+`
+
+// testRepository creates a git repository in a temporary directory and returns its articles directory,
+// so included files can be anywhere in the repository
+func testRepository(t *testing.T) string {
+	t.Helper()
+
+	repo := t.TempDir()
+	err := os.Mkdir(filepath.Join(repo, ".git"), 0755)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	root := filepath.Join(repo, "articles")
+	err = os.Mkdir(root, 0755)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return root
+}
+
+func TestExpandIncludes(t *testing.T) {
+	root := testRepository(t)
+	dir := writeTestArticle(t, root, "a", &Article{Title: "A"}, "")
+	err := os.MkdirAll(filepath.Join(root, "examples"), 0755)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = os.WriteFile(filepath.Join(root, "examples", "main.go"), []byte(testIncludeSource), 0640)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = os.WriteFile(filepath.Join(root, "examples", "README.md"), []byte("```go\ncode\n```\n"), 0640)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		body          string
+		expected      string
+		expectedError string
+	}{
+		{
+			"WholeFile",
+			"Intro\n<!-- include ../examples/main.go -->\nOutro\n",
+			"Intro\n```go\n" + strings.TrimSuffix(testIncludeSource, "\n") + "\n```\nOutro\n",
+			"",
+		},
+		{
+			"Lines",
+			"<!-- include ../examples/main.go lines=5-5 lang=golang -->",
+			"```golang\nfunc main() {\n```",
+			"",
+		},
+		{
+			"LinesToEnd",
+			"<!-- include ../examples/main.go lines=12- -->\n",
+			"```go\n}\n```\n",
+			"",
+		},
+		{
+			"Region",
+			"- step one:\n  <!-- include ../examples/main.go region=greeting -->\n",
+			"- step one:\n  ```go\n  name := \"World\"\n  fmt.Printf(\"Hello, %s\\n\", name)\n  ```\n",
+			"",
+		},
+		{
+			"LongerFence",
+			"<!-- include ../examples/README.md lang=md -->\n",
+			"````md\n```go\ncode\n```\n````\n",
+			"",
+		},
+		{
+			"InCodeBlock",
+			"```md\n<!-- include ../examples/main.go -->\n```\n",
+			"```md\n<!-- include ../examples/main.go -->\n```\n",
+			"",
+		},
+		{
+			"MissingFile",
+			"\n<!-- include ../examples/missing.go -->\n",
+			"",
+			"error including ../examples/missing.go on line 2: error reading included file:",
+		},
+		{
+			"OutsideRepository",
+			"<!-- include ../../../etc/passwd -->\n",
+			"",
+			"included file ../../../etc/passwd is outside of " + filepath.Dir(root),
+		},
+		{
+			"MissingRegion",
+			"<!-- include ../examples/main.go region=missing -->\n",
+			"",
+			`region "missing" not found`,
+		},
+		{
+			"PastEnd",
+			"<!-- include ../examples/main.go lines=10-20 -->\n",
+			"",
+			"included lines 10-20 are past the end of ../examples/main.go, which has 12 lines",
+		},
+		{
+			"InvalidOptions",
+			"<!-- include ../examples/main.go lines=3 region=greeting -->\n",
+			"",
+			"include can't use both lines and region",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, expanded, err := expandIncludes(dir, tt.body)
+			if tt.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Fatalf("expected error %q but got: %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Fatalf("unexpected result:\n%s", result)
+			}
+
+			collapsed := collapseIncludes(result, expanded)
+			if collapsed != tt.body {
+				t.Fatalf("expected collapsing to return the original body but got:\n%s", collapsed)
+			}
+		})
+	}
+}
+
+func TestValidateIncludes(t *testing.T) {
+	root := testRepository(t)
+	dir := writeTestArticle(t, root, "a", &Article{Title: "A"}, "")
+	body := "<!-- include missing.go -->\n<!-- include missing.go color=red -->\n<!-- include missing.go lines=0-2 -->\n<!-- include ../../../secret -->\n"
+
+	var result []string
+	for _, p := range validateIncludes(dir, body) {
+		result = append(result, fmt.Sprintf("%d: %s", p.Line, p.Message))
+	}
+
+	expected := []string{
+		"1: error reading included file: open " + filepath.Join(dir, "missing.go") + ": no such file or directory",
+		`2: unknown include option "color"`,
+		`3: invalid include lines "0-2": lines start at 1`,
+		"4: included file ../../../secret is outside of " + filepath.Dir(root),
+	}
+	if !slices.Equal(result, expected) {
+		t.Fatalf("unexpected problems:\n%s", strings.Join(result, "\n"))
+	}
+}
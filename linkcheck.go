@@ -0,0 +1,365 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// linkConfig configures the link check. Local links are always checked, but external links are only
+// checked if External is set since it needs network access. Allow has hosts, like "example.com", or URL
+// prefixes, like "https://example.com/private/", which are not checked
+type linkConfig struct {
+	External    bool     `json:"external,omitempty"`
+	Allow       []string `json:"allow,omitempty"`
+	Concurrency int      `json:"concurrency,omitempty"`
+	Timeout     string   `json:"timeout,omitempty"`
+}
+
+// allowed is true if the URL matches an allowed host, including its subdomains, or URL prefix
+func (c linkConfig) allowed(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	for _, allow := range c.Allow {
+		if strings.Contains(allow, "://") {
+			if strings.HasPrefix(rawURL, allow) {
+				return true
+			}
+			continue
+		}
+		if u.Hostname() == allow || strings.HasSuffix(u.Hostname(), "."+allow) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// linkResolver checks if an external URL works. It is an interface so tests don't need network access
+type linkResolver interface {
+	resolve(ctx context.Context, url string) error
+}
+
+// httpResolver checks URLs with HEAD requests. Some servers don't support HEAD, so those are retried with GET
+type httpResolver struct {
+	client *http.Client
+}
+
+func newHTTPResolver(cfg linkConfig) (*httpResolver, error) {
+	timeout := 10 * time.Second
+	if cfg.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing link check timeout: %w", err)
+		}
+	}
+
+	return &httpResolver{&http.Client{Timeout: timeout}}, nil
+}
+
+func (r *httpResolver) resolve(ctx context.Context, url string) error {
+	status, err := r.do(ctx, http.MethodHead, url)
+	if status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented {
+		status, err = r.do(ctx, http.MethodGet, url)
+	}
+	if err != nil {
+		return err
+	}
+
+	if status >= 400 {
+		return fmt.Errorf("%d %s", status, http.StatusText(status))
+	}
+
+	return nil
+}
+
+func (r *httpResolver) do(ctx context.Context, method, url string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "article-sync link check")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
+
+// markdownLink is a link target found on a line of an article
+type markdownLink struct {
+	Line   int
+	Target string
+}
+
+var (
+	inlineLinkPattern     = regexp.MustCompile(`!?\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+"[^"]*")?\s*\)`)
+	autolinkPattern       = regexp.MustCompile(`<(https?://[^>\s]+)>`)
+	referenceLinkPattern  = regexp.MustCompile(`^\s{0,3}\[[^\]]+\]:\s*<?([^\s>]+)>?`)
+	htmlAnchorPattern     = regexp.MustCompile(`(?:id|name)="([^"]+)"`)
+	anchorRemovedPattern  = regexp.MustCompile(`[^\p{L}\p{N}\s_-]`)
+	externalSchemePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+)
+
+// extractLinks finds the targets of links, images, autolinks, reference definitions, and bare URLs outside
+// of code. Liquid tags are skipped since they are checked by validate
+func extractLinks(body string) []markdownLink {
+	var links []markdownLink
+
+	var fence codeFence
+	for i, line := range splitLines(body) {
+		n := i + 1
+
+		if fence.code(line) {
+			continue
+		}
+
+		line = inlineCodePattern.ReplaceAllString(line, "")
+		line = liquidTagPattern.ReplaceAllString(line, "")
+
+		if match := referenceLinkPattern.FindStringSubmatch(line); match != nil {
+			links = append(links, markdownLink{n, match[1]})
+			continue
+		}
+
+		for _, match := range inlineLinkPattern.FindAllStringSubmatch(line, -1) {
+			links = append(links, markdownLink{n, match[1]})
+		}
+		for _, match := range autolinkPattern.FindAllStringSubmatch(line, -1) {
+			links = append(links, markdownLink{n, match[1]})
+		}
+		for _, u := range urlPattern.FindAllString(notProse.ReplaceAllString(line, ""), -1) {
+			links = append(links, markdownLink{n, strings.TrimRight(u, ".,;:!?'\"")})
+		}
+	}
+
+	return links
+}
+
+// headingAnchors returns the anchors dev.to and GitHub create for headings: lowercase with punctuation
+// removed and spaces replaced by hyphens. Repeated headings get a numbered suffix. Anchors from HTML
+// id and name attributes are also included
+func headingAnchors(body string) map[string]bool {
+	anchors := map[string]bool{}
+	counts := map[string]int{}
+
+	var fence codeFence
+	for _, line := range splitLines(body) {
+		if fence.code(line) {
+			continue
+		}
+
+		for _, match := range htmlAnchorPattern.FindAllStringSubmatch(line, -1) {
+			anchors[match[1]] = true
+		}
+
+		match := headingPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		text := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#"))
+		text = strings.TrimRight(text, "# ")
+		anchor := strings.ToLower(anchorRemovedPattern.ReplaceAllString(text, ""))
+		anchor = strings.Join(strings.Fields(anchor), "-")
+
+		if count := counts[anchor]; count > 0 {
+			anchors[fmt.Sprintf("%s-%d", anchor, count)] = true
+		} else {
+			anchors[anchor] = true
+		}
+		counts[anchor]++
+	}
+
+	return anchors
+}
+
+// checkLinks finds broken links in every article. Anchors, links to other articles, and files are checked
+// locally. External URLs are only checked with a resolver, and each URL is only resolved once
+func checkLinks(ctx context.Context, rootDir string, cfg linkConfig, resolver linkResolver) ([]lintFinding, error) {
+	dirs, err := findArticleDirectories(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	type externalLink struct {
+		file string
+		markdownLink
+	}
+
+	var (
+		findings []lintFinding
+		external []externalLink
+		anchors  = map[string]map[string]bool{}
+	)
+
+	// anchorsFor caches the anchors of each article.md since articles can link to each other many times
+	anchorsFor := func(path string) (map[string]bool, error) {
+		if a, ok := anchors[path]; ok {
+			return a, nil
+		}
+		body, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		anchors[path] = headingAnchors(string(body))
+		return anchors[path], nil
+	}
+
+	for _, dir := range dirs {
+		path := filepath.Join(dir, "article.md")
+		body, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading markdown: %w", err)
+		}
+
+		for _, link := range extractLinks(string(body)) {
+			if externalSchemePattern.MatchString(link.Target) {
+				if strings.HasPrefix(link.Target, "http://") || strings.HasPrefix(link.Target, "https://") {
+					external = append(external, externalLink{path, link})
+				}
+				continue
+			}
+
+			problem, err := checkLocalLink(dir, link.Target, anchorsFor)
+			if err != nil {
+				return nil, err
+			}
+			if problem != "" {
+				findings = append(findings, lintFinding{path, link.Line, lintBrokenLink, fmt.Sprintf("broken link %s: %s", link.Target, problem)})
+			}
+		}
+	}
+
+	if resolver == nil {
+		return findings, nil
+	}
+
+	var urls []string
+	for _, link := range external {
+		if !cfg.allowed(link.Target) {
+			urls = append(urls, link.Target)
+		}
+	}
+	results := resolveConcurrently(ctx, resolver, urls, cfg.Concurrency)
+
+	for _, link := range external {
+		if err, ok := results[link.Target]; ok && err != nil {
+			findings = append(findings, lintFinding{link.file, link.Line, lintBrokenLink, fmt.Sprintf("broken link %s: %v", link.Target, err)})
+		}
+	}
+
+	return findings, ctx.Err()
+}
+
+// checkLocalLink returns a description of the problem with a link relative to the article directory, or an
+// empty string if it works. A link to another article's directory or article.md can have an anchor in it
+func checkLocalLink(dir, target string, anchorsFor func(string) (map[string]bool, error)) (string, error) {
+	target, anchor, _ := strings.Cut(target, "#")
+	target, _, _ = strings.Cut(target, "?")
+
+	target, err := url.PathUnescape(target)
+	if err != nil {
+		return "invalid path", nil
+	}
+
+	// absolute paths are relative to dev.to, so they can't be checked locally
+	if strings.HasPrefix(target, "/") {
+		return "", nil
+	}
+
+	markdownPath := filepath.Join(dir, "article.md")
+	if target != "" {
+		path := filepath.Join(dir, filepath.FromSlash(target))
+		info, err := os.Stat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return "file does not exist", nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("error checking link: %w", err)
+		}
+
+		switch {
+		case info.IsDir():
+			markdownPath = filepath.Join(path, "article.md")
+			if _, err := os.Stat(markdownPath); err != nil {
+				return "directory is not an article", nil
+			}
+		case filepath.Base(path) == "article.md":
+			markdownPath = path
+		default:
+			return "", nil
+		}
+	}
+
+	if anchor == "" {
+		return "", nil
+	}
+
+	anchors, err := anchorsFor(markdownPath)
+	if err != nil {
+		return "", fmt.Errorf("error reading markdown: %w", err)
+	}
+	if !anchors[anchor] {
+		return fmt.Sprintf("no heading for #%s", anchor), nil
+	}
+
+	return "", nil
+}
+
+// resolveConcurrently resolves each unique URL once with a limited number of workers
+func resolveConcurrently(ctx context.Context, resolver linkResolver, urls []string, concurrency int) map[string]error {
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	var (
+		mu      sync.Mutex
+		results = map[string]error{}
+		wg      sync.WaitGroup
+	)
+
+	work := make(chan string)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range work {
+				err := resolver.resolve(ctx, u)
+
+				mu.Lock()
+				results[u] = err
+				mu.Unlock()
+			}
+		}()
+	}
+
+	seen := map[string]bool{}
+	for _, u := range urls {
+		if seen[u] || ctx.Err() != nil {
+			continue
+		}
+		seen[u] = true
+		work <- u
+	}
+	close(work)
+	wg.Wait()
+
+	return results
+}
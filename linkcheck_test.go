@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeResolver fails URLs in broken and records every URL it resolves
+type fakeResolver struct {
+	broken map[string]error
+
+	mu       sync.Mutex
+	resolved []string
+}
+
+func (r *fakeResolver) resolve(_ context.Context, url string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resolved = append(r.resolved, url)
+	return r.broken[url]
+}
+
+func TestExtractLinks(t *testing.T) {
+	body := "## Intro\n\nSee [docs](https://go.dev \"Go\") and ![gopher](./gopher.png).\n" +
+		"Visit <https://dev.to> or https://example.com/page.\n\n```\n[code](https://in.code)\n```\n" +
+		"`[inline](https://in.code)` {% embed https://in.liquid %}\n[ref]: ../other#setup\n"
+
+	var result []string
+	for _, link := range extractLinks(body) {
+		result = append(result, fmt.Sprintf("%d: %s", link.Line, link.Target))
+	}
+
+	expected := []string{
+		"3: https://go.dev",
+		"3: ./gopher.png",
+		"4: https://dev.to",
+		"4: https://example.com/page",
+		"10: ../other#setup",
+	}
+	if !slices.Equal(result, expected) {
+		t.Fatalf("unexpected links:\n%s", strings.Join(result, "\n"))
+	}
+}
+
+func TestHeadingAnchors(t *testing.T) {
+	anchors := headingAnchors("## Getting Started!\n\n### Getting Started\n\n```sh\n# not a heading\n```\n\n## What's `new` in Go 1.22? ##\n\n<a name=\"custom\"></a>\n")
+
+	for _, anchor := range []string{"getting-started", "getting-started-1", "whats-new-in-go-122", "custom"} {
+		if !anchors[anchor] {
+			t.Errorf("missing anchor %q in %v", anchor, anchors)
+		}
+	}
+	if anchors["not-a-heading"] {
+		t.Errorf("unexpected anchor from code block")
+	}
+}
+
+func TestCheckLinks(t *testing.T) {
+	root := t.TempDir()
+	dir := writeTestArticle(t, root, "a", &Article{Title: "A"}, "## Intro\n\n"+
+		"[intro](#intro) [missing](#missing)\n"+
+		"[b](../b) [b setup](../b/article.md#setup) [b missing](../b#missing)\n"+
+		"[nowhere](../..) [image](image.png) [no image](missing.png) [dev.to](/tester/post)\n"+
+		"[ok](https://ok.example.com) [broken](https://broken.example.com/page) [again](https://broken.example.com/page)\n"+
+		"[allowed](https://private.example.com/page) [mail](mailto:me@example.com)\n")
+	writeTestArticle(t, root, "b", &Article{Title: "B"}, "## Setup\n")
+
+	err := os.WriteFile(filepath.Join(dir, "image.png"), nil, 0640)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name             string
+		resolver         *fakeResolver
+		expectedResolved []string
+		expected         []string
+	}{
+		{
+			"LocalOnly",
+			nil,
+			nil,
+			[]string{
+				"a/article.md:3: broken link #missing: no heading for #missing (broken-link)",
+				"a/article.md:4: broken link ../b#missing: no heading for #missing (broken-link)",
+				"a/article.md:5: broken link ../..: directory is not an article (broken-link)",
+				"a/article.md:5: broken link missing.png: file does not exist (broken-link)",
+			},
+		},
+		{
+			"External",
+			&fakeResolver{broken: map[string]error{"https://broken.example.com/page": errors.New("404 Not Found")}},
+			[]string{"https://broken.example.com/page", "https://ok.example.com"},
+			[]string{
+				"a/article.md:3: broken link #missing: no heading for #missing (broken-link)",
+				"a/article.md:4: broken link ../b#missing: no heading for #missing (broken-link)",
+				"a/article.md:5: broken link ../..: directory is not an article (broken-link)",
+				"a/article.md:5: broken link missing.png: file does not exist (broken-link)",
+				"a/article.md:6: broken link https://broken.example.com/page: 404 Not Found (broken-link)",
+				"a/article.md:6: broken link https://broken.example.com/page: 404 Not Found (broken-link)",
+			},
+		},
+	}
+
+	cfg := linkConfig{Allow: []string{"https://private.example.com/"}, Concurrency: 2}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resolver linkResolver
+			if tt.resolver != nil {
+				resolver = tt.resolver
+			}
+
+			findings, err := checkLinks(context.Background(), root, cfg, resolver)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var result []string
+			for _, f := range findings {
+				rel, _ := filepath.Rel(root, f.File)
+				f.File = filepath.ToSlash(rel)
+				result = append(result, f.String())
+			}
+			if !slices.Equal(result, tt.expected) {
+				t.Fatalf("unexpected findings:\n%s", strings.Join(result, "\n"))
+			}
+
+			if tt.resolver != nil {
+				slices.Sort(tt.resolver.resolved)
+				if !slices.Equal(tt.resolver.resolved, tt.expectedResolved) {
+					t.Fatalf("unexpected resolved URLs: %v", tt.resolver.resolved)
+				}
+			}
+		})
+	}
+}
+
+func TestLinkConfigAllowed(t *testing.T) {
+	cfg := linkConfig{Allow: []string{"example.com", "https://go.dev/private/"}}
+
+	tests := []struct {
+		url      string
+		expected bool
+	}{
+		{"https://example.com/page", true},
+		{"https://docs.example.com", true},
+		{"https://notexample.com", false},
+		{"https://go.dev/private/page", true},
+		{"https://go.dev/doc", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if cfg.allowed(tt.url) != tt.expected {
+				t.Fatalf("expected %t", tt.expected)
+			}
+		})
+	}
+}
+
+func TestHTTPResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/ok" && r.Method == http.MethodHead:
+		case r.URL.Path == "/no-head" && r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case r.URL.Path == "/no-head" && r.Method == http.MethodGet:
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resolver, err := newHTTPResolver(linkConfig{Timeout: "5s"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for path, expected := range map[string]string{"/ok": "", "/no-head": "", "/missing": "404 Not Found"} {
+		var result string
+		if err := resolver.resolve(context.Background(), server.URL+path); err != nil {
+			result = err.Error()
+		}
+		if result != expected {
+			t.Errorf("unexpected error for %s: %q", path, result)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// lint rules can be disabled in the lint section of the config
+const (
+	lintHeadingLevels      = "heading-levels"
+	lintFenceLanguage      = "fence-language"
+	lintUnclosedFence      = "unclosed-fence"
+	lintTrailingWhitespace = "trailing-whitespace"
+	lintImageAlt           = "image-alt"
+	lintBareURL            = "bare-url"
+	lintLineLength         = "line-length"
+	lintBrokenLink         = "broken-link"
+)
+
+// lintConfig configures the lint rules. line-length is only checked if LineLength is set
+type lintConfig struct {
+	Disable    []string `json:"disable,omitempty"`
+	LineLength int      `json:"line_length,omitempty"`
+}
+
+func (c lintConfig) enabled(rule string) bool {
+	if rule == lintLineLength && c.LineLength <= 0 {
+		return false
+	}
+	return !slices.Contains(c.Disable, rule)
+}
+
+// lintFinding is a problem in an article's markdown. Findings are warnings, so they don't stop synchronizing
+type lintFinding struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (f lintFinding) String() string {
+	return fmt.Sprintf("%s:%d: %s (%s)", f.File, f.Line, f.Message, f.Rule)
+}
+
+var (
+	fencePattern   = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})\\s*(.*)$")
+	headingPattern = regexp.MustCompile(`^ {0,3}(#{1,6})(\s|$)`)
+	emptyAltImage  = regexp.MustCompile(`!\[\s*\]\(`)
+	urlPattern     = regexp.MustCompile(`https?://[^\s<>()\[\]]+`)
+
+	// notProse matches markdown that can contain URLs without them being bare: inline code, links,
+	// images, autolinks and HTML, liquid tags, and link reference definitions
+	notProse = regexp.MustCompile("`[^`]*`|!?\\[[^\\]]*\\]\\([^)]*\\)|<[^>]*>|\\{%.*?%\\}|^\\s*\\[[^\\]]+\\]:\\s*\\S+")
+)
+
+// codeFence tracks fenced code blocks while markdown is read line by line
+type codeFence struct {
+	fence string
+
+	// info is the text after the opening fence, like the language, of the current code block
+	info string
+}
+
+// code reads the next line and reports whether it is a fence or inside a code block, so it isn't prose.
+// A newline at the end of the line is ignored
+func (f *codeFence) code(line string) bool {
+	match := fencePattern.FindStringSubmatch(strings.TrimRight(line, "\n"))
+	if match == nil {
+		return f.fence != ""
+	}
+
+	switch {
+	case f.fence == "":
+		f.fence, f.info = match[1], match[2]
+	case match[1][0] == f.fence[0] && len(match[1]) >= len(f.fence) && match[2] == "":
+		f.fence, f.info = "", ""
+	}
+	return true
+}
+
+// open reports whether the last line read is in a code block that isn't closed yet
+func (f *codeFence) open() bool {
+	return f.fence != ""
+}
+
+// lintArticles lints the markdown of every article in rootDir
+func lintArticles(rootDir string, cfg lintConfig) ([]lintFinding, error) {
+	dirs, err := findArticleDirectories(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []lintFinding
+	for _, dir := range dirs {
+		path := filepath.Join(dir, "article.md")
+		body, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading markdown: %w", err)
+		}
+
+		for _, f := range lintMarkdown(string(body), cfg) {
+			f.File = path
+			result = append(result, f)
+		}
+	}
+
+	return result, nil
+}
+
+// lintMarkdown checks the markdown line by line. Code blocks are skipped by every rule except the ones
+// about fences and trailing whitespace. The title is the article's only top-level heading, so headings
+// in the body start at level 2
+func lintMarkdown(body string, cfg lintConfig) []lintFinding {
+	var findings []lintFinding
+	add := func(line int, rule, format string, args ...any) {
+		if cfg.enabled(rule) {
+			findings = append(findings, lintFinding{Line: line, Rule: rule, Message: fmt.Sprintf(format, args...)})
+		}
+	}
+
+	var fence codeFence
+	fenceLine := 0
+	headingLevel := 1
+	for i, line := range splitLines(body) {
+		n := i + 1
+
+		if strings.TrimRight(line, " \t") != line {
+			add(n, lintTrailingWhitespace, "trailing whitespace")
+		}
+
+		wasOpen := fence.open()
+		if fence.code(line) {
+			if !wasOpen && fence.open() {
+				fenceLine = n
+				if fence.info == "" {
+					add(n, lintFenceLanguage, "code block has no language")
+				}
+			}
+			continue
+		}
+
+		if match := headingPattern.FindStringSubmatch(line); match != nil {
+			level := len(match[1])
+			switch {
+			case level == 1:
+				add(n, lintHeadingLevels, "level 1 heading in the body: the title is the only level 1 heading, so start at level 2")
+			case level > headingLevel+1:
+				add(n, lintHeadingLevels, "heading level %d skips level %d", level, headingLevel+1)
+			}
+			headingLevel = level
+		}
+
+		if emptyAltImage.MatchString(line) {
+			add(n, lintImageAlt, "image has no alt text")
+		}
+
+		if url := urlPattern.FindString(notProse.ReplaceAllString(line, "")); url != "" {
+			add(n, lintBareURL, "bare URL %s: use a link or <%s>", url, url)
+		}
+
+		if length := utf8.RuneCountInString(line); cfg.LineLength > 0 && length > cfg.LineLength && isProse(line) {
+			add(n, lintLineLength, "line is %d characters, longer than %d", length, cfg.LineLength)
+		}
+	}
+
+	if fence.open() {
+		add(fenceLine, lintUnclosedFence, "code block is never closed")
+	}
+
+	return findings
+}
+
+// isProse is false for table rows and lines without spaces, like a long URL, which can't be wrapped
+func isProse(line string) bool {
+	line = strings.TrimSpace(line)
+	return !strings.HasPrefix(line, "|") && strings.ContainsAny(line, " \t")
+}
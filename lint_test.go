@@ -0,0 +1,98 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLintMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		cfg      lintConfig
+		expected []string
+	}{
+		{
+			"Valid",
+			"## Intro\n\nSee [the docs](https://go.dev) or <https://go.dev>.\n\n### Details\n\n```go\nfmt.Println(\"https://example.com\")\n```\n\n![gopher](gopher.png)\n\n{% embed https://dev.to %}\n",
+			lintConfig{},
+			nil,
+		},
+		{
+			"HeadingLevels",
+			"# Title\n\n## Intro\n\n#### Details\n",
+			lintConfig{},
+			[]string{
+				"1: level 1 heading in the body: the title is the only level 1 heading, so start at level 2 (heading-levels)",
+				"5: heading level 4 skips level 3 (heading-levels)",
+			},
+		},
+		{
+			"Fences",
+			"```\ncode\n```\n\n~~~sh\nls\n```\n",
+			lintConfig{},
+			[]string{
+				"1: code block has no language (fence-language)",
+				"5: code block is never closed (unclosed-fence)",
+			},
+		},
+		{
+			"TrailingWhitespaceAndImageAlt",
+			"Hello \n![](image.png)\n",
+			lintConfig{},
+			[]string{
+				"1: trailing whitespace (trailing-whitespace)",
+				"2: image has no alt text (image-alt)",
+			},
+		},
+		{
+			"BareURL",
+			"Read https://go.dev/doc for more.\n\n[ref]: https://go.dev\n\n`https://in.code`\n",
+			lintConfig{},
+			[]string{"1: bare URL https://go.dev/doc: use a link or <https://go.dev/doc> (bare-url)"},
+		},
+		{
+			"LineLength",
+			"This line is too long for the limit.\n| this | table | row | is | also | too | long |\nhttps://example.com/a/very/long/url/without/spaces\n",
+			lintConfig{LineLength: 20, Disable: []string{lintBareURL}},
+			[]string{"1: line is 36 characters, longer than 20 (line-length)"},
+		},
+		{
+			"DisabledRules",
+			"# Title\n```\n",
+			lintConfig{Disable: []string{lintHeadingLevels, lintFenceLanguage, lintUnclosedFence}},
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result []string
+			for _, f := range lintMarkdown(tt.body, tt.cfg) {
+				result = append(result, strings.TrimPrefix(f.String(), ":"))
+			}
+
+			if !slices.Equal(result, tt.expected) {
+				t.Fatalf("unexpected findings:\n%s", strings.Join(result, "\n"))
+			}
+		})
+	}
+}
+
+func TestCodeFence(t *testing.T) {
+	lines := []string{"prose", "````go", "```", "still code", "````", "~~~\n", "~~~\n", "prose", "```"}
+	expected := []bool{false, true, true, true, true, true, true, false, true}
+
+	var fence codeFence
+	var result []bool
+	for _, line := range lines {
+		result = append(result, fence.code(line))
+	}
+	if !slices.Equal(result, expected) {
+		t.Fatalf("unexpected result: %v", result)
+	}
+	if !fence.open() {
+		t.Fatal("expected the last code block to be open")
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// liquidTagSpec describes the arguments dev.to accepts for a liquid tag. The first argument must match
+// arg, which is described by argDescription in errors. Block tags must be closed with an end tag
+type liquidTagSpec struct {
+	arg            *regexp.Regexp
+	argDescription string
+	minArgs        int
+	// maxArgs is the maximum number of arguments or -1 for no limit
+	maxArgs int
+	// freeText tags take any text as their argument, like the summary of details
+	freeText bool
+	block    bool
+}
+
+var (
+	liquidURL  = regexp.MustCompile(`^https?://\S+$`)
+	liquidSlug = regexp.MustCompile(`^[\w-]+$`)
+)
+
+// knownLiquidTags are the liquid tags supported by dev.to: https://dev.to/p/editor_guide
+var knownLiquidTags = map[string]liquidTagSpec{
+	"embed":             {liquidURL, "a URL", 1, 1, false, false},
+	"link":              {regexp.MustCompile(`^(https?://\S+|/\S+)$`), "a dev.to URL or path", 1, 1, false, false},
+	"post":              {regexp.MustCompile(`^(https?://\S+|/?[\w-]+/[\w-]+)$`), "a dev.to URL or username/slug", 1, 1, false, false},
+	"github":            {regexp.MustCompile(`^(https?://github\.com/\S+|[\w.-]+/[\w.-]+\S*)$`), "a GitHub URL or owner/repo", 1, 2, false, false},
+	"gist":              {regexp.MustCompile(`^https://gist\.github\.com/\S+$`), "a gist URL", 1, 2, false, false},
+	"youtube":           {regexp.MustCompile(`^([\w-]{11}(\?\S*)?|https?://\S+)$`), "a YouTube video ID or URL", 1, 1, false, false},
+	"vimeo":             {regexp.MustCompile(`^(\d+|https?://\S+)$`), "a Vimeo video ID or URL", 1, 1, false, false},
+	"twitter":           {regexp.MustCompile(`^(\d+|https?://\S+)$`), "a tweet ID or URL", 1, 1, false, false},
+	"tweet":             {regexp.MustCompile(`^(\d+|https?://\S+)$`), "a tweet ID or URL", 1, 1, false, false},
+	"codepen":           {liquidURL, "a CodePen URL", 1, 3, false, false},
+	"jsfiddle":          {liquidURL, "a JSFiddle URL", 1, 2, false, false},
+	"codesandbox":       {liquidSlug, "a CodeSandbox ID", 1, -1, false, false},
+	"stackblitz":        {liquidSlug, "a StackBlitz ID", 1, -1, false, false},
+	"glitch":            {liquidSlug, "a Glitch project name", 1, -1, false, false},
+	"replit":            {regexp.MustCompile(`^@?[\w-]+/[\w.-]+$`), "a Replit @user/repl", 1, 1, false, false},
+	"instagram":         {liquidSlug, "an Instagram post ID", 1, 1, false, false},
+	"speakerdeck":       {liquidSlug, "a Speaker Deck ID", 1, 1, false, false},
+	"slideshare":        {liquidSlug, "a SlideShare key", 1, 1, false, false},
+	"spotify":           {regexp.MustCompile(`^spotify:\S+$`), "a Spotify URI", 1, 1, false, false},
+	"twitch":            {liquidSlug, "a Twitch clip slug", 1, 1, false, false},
+	"asciinema":         {regexp.MustCompile(`^\d+$`), "an asciinema ID", 1, 1, false, false},
+	"wikipedia":         {liquidURL, "a Wikipedia URL", 1, 1, false, false},
+	"reddit":            {liquidURL, "a Reddit URL", 1, 1, false, false},
+	"stackexchange":     {regexp.MustCompile(`^(\d+|https?://\S+)$`), "a Stack Exchange ID or URL", 1, 2, false, false},
+	"stackoverflow":     {regexp.MustCompile(`^(\d+|https?://\S+)$`), "a Stack Overflow ID or URL", 1, 2, false, false},
+	"medium":            {liquidURL, "a Medium URL", 1, 1, false, false},
+	"kotlin":            {liquidURL, "a Kotlin Playground URL", 1, 1, false, false},
+	"dotnetfiddle":      {liquidURL, "a .NET Fiddle URL", 1, 1, false, false},
+	"jsitor":            {nil, "a JSitor ID or URL", 1, -1, false, false},
+	"soundcloud":        {liquidURL, "a SoundCloud URL", 1, 1, false, false},
+	"loom":              {liquidURL, "a Loom URL", 1, 1, false, false},
+	"blogcast":          {liquidSlug, "a Blogcast ID", 1, 1, false, false},
+	"poll":              {regexp.MustCompile(`^\d+$`), "a poll ID", 1, 1, false, false},
+	"listing":           {nil, "a listing path", 1, 1, false, false},
+	"devcomment":        {liquidSlug, "a dev.to comment ID", 1, 1, false, false},
+	"twitter_timeline":  {liquidURL, "a Twitter timeline URL", 1, 1, false, false},
+	"user_subscription": {nil, "a call to action", 0, -1, true, false},
+	"podcast":           {liquidURL, "a podcast episode URL", 1, 1, false, false},
+	"user":              {liquidSlug, "a dev.to username", 1, 1, false, false},
+	"tag":               {regexp.MustCompile(`^[a-z0-9]+$`), "a dev.to tag", 1, 1, false, false},
+	"details":           {nil, "a summary", 1, -1, true, true},
+	"spoiler":           {nil, "a summary", 1, -1, true, true},
+	"collapsible":       {nil, "a summary", 1, -1, true, true},
+	"katex":             {regexp.MustCompile(`^inline$`), `"inline"`, 0, 1, false, true},
+	"cta":               {liquidURL, "a URL", 1, 1, false, true},
+	"card":              {nil, "", 0, 0, false, true},
+	"runkit":            {nil, "", 0, -1, false, true},
+	"raw":               {nil, "", 0, 0, false, true},
+	"comment":           {nil, "", 0, 0, false, true},
+}
+
+var (
+	liquidTagPattern  = regexp.MustCompile(`\{%-?\s*(\S*)\s*(.*?)\s*-?%\}`)
+	inlineCodePattern = regexp.MustCompile("`[^`]*`")
+)
+
+// liquidProblem is an invalid liquid tag on a line of the article body
+type liquidProblem struct {
+	Line    int
+	Message string
+}
+
+// validateLiquidTags finds liquid tags outside of code and checks their names and arguments. The content
+// of raw and comment blocks is not checked since dev.to doesn't render it
+func validateLiquidTags(body string) []liquidProblem {
+	var problems []liquidProblem
+	add := func(line int, format string, args ...any) {
+		problems = append(problems, liquidProblem{line, fmt.Sprintf(format, args...)})
+	}
+
+	type openBlock struct {
+		name string
+		line int
+	}
+	var blocks []openBlock
+
+	var fence codeFence
+	for i, line := range splitLines(body) {
+		n := i + 1
+
+		if fence.code(line) {
+			continue
+		}
+
+		line = inlineCodePattern.ReplaceAllString(line, "")
+		for _, match := range liquidTagPattern.FindAllStringSubmatch(line, -1) {
+			name, args := match[1], match[2]
+
+			if len(blocks) > 0 && slices.Contains([]string{"raw", "comment"}, blocks[len(blocks)-1].name) && name != "end"+blocks[len(blocks)-1].name {
+				continue
+			}
+
+			if blockName, ok := strings.CutPrefix(name, "end"); ok && knownLiquidTags[blockName].block {
+				if len(blocks) == 0 || blocks[len(blocks)-1].name != blockName {
+					add(n, "{%% %s %%} does not close a {%% %s %%} tag", name, blockName)
+					continue
+				}
+				blocks = blocks[:len(blocks)-1]
+				continue
+			}
+
+			spec, ok := knownLiquidTags[name]
+			if !ok {
+				if suggestion := closestLiquidTag(name); suggestion != "" {
+					add(n, "unknown liquid tag %q: did you mean %q?", name, suggestion)
+				} else {
+					add(n, "unknown liquid tag %q", name)
+				}
+				continue
+			}
+
+			if msg := spec.checkArgs(name, args); msg != "" {
+				add(n, "%s", msg)
+			}
+
+			if spec.block {
+				blocks = append(blocks, openBlock{name, n})
+			}
+		}
+
+		if strings.Count(line, "{%") > len(liquidTagPattern.FindAllString(line, -1)) {
+			add(n, "liquid tag is not closed with %%}")
+		}
+	}
+
+	for _, b := range blocks {
+		add(b.line, "{%% %s %%} is never closed with {%% end%s %%}", b.name, b.name)
+	}
+
+	return problems
+}
+
+func (s liquidTagSpec) checkArgs(name, args string) string {
+	fields := strings.Fields(args)
+	switch {
+	case len(fields) < s.minArgs:
+		return fmt.Sprintf("{%% %s %%} needs %s", name, s.argDescription)
+	case s.freeText:
+		return ""
+	case s.maxArgs >= 0 && len(fields) > s.maxArgs:
+		return fmt.Sprintf("{%% %s %%} has %d arguments but accepts at most %d", name, len(fields), s.maxArgs)
+	case len(fields) > 0 && s.arg != nil && !s.arg.MatchString(fields[0]):
+		return fmt.Sprintf("{%% %s %%} needs %s but got %q", name, s.argDescription, fields[0])
+	}
+	return ""
+}
+
+// closestLiquidTag suggests a known tag for a typo. It returns an empty string if no tag is close
+func closestLiquidTag(name string) string {
+	best, bestDistance := "", 3
+	for known := range knownLiquidTags {
+		d := editDistance(name, known)
+		if d < bestDistance || d == bestDistance && known < best {
+			best, bestDistance = known, d
+		}
+	}
+	if bestDistance > 2 {
+		return ""
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+
+	return prev[len(b)]
+}
+
+// standaloneLink matches a line that only has a link or URL, which dev.to can show as an embed
+var standaloneLink = regexp.MustCompile(`^\s*(?:\[[^\]]*\]\((https?://[^\s)]+)\)|<?(https?://[^\s>]+)>?)\s*$`)
+
+// embedLinks replaces links and URLs that are alone on a line with {% embed %} tags, so dev.to shows a
+// preview card while the markdown stays portable. Code blocks are left alone
+func embedLinks(body string) string {
+	lines := strings.SplitAfter(body, "\n")
+
+	var fence codeFence
+	for i, line := range lines {
+		if fence.code(line) {
+			continue
+		}
+
+		text := strings.TrimRight(line, "\n")
+		match := standaloneLink.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+
+		url := match[1] + match[2]
+		lines[i] = fmt.Sprintf("{%% embed %s %%}", url) + line[len(text):]
+	}
+
+	return strings.Join(lines, "")
+}
+
+// embedTagPattern matches an embed tag alone on a line, like embedLinks writes
+var embedTagPattern = regexp.MustCompile(`^\s*\{%\s*embed\s+(\S+)\s*%\}\s*$`)
+
+// unembedLinks changes embed tags in body back to the standalone links in original that embedLinks replaced.
+// Embed tags that weren't created from a link in original are kept
+func unembedLinks(body, original string) string {
+	links := map[string]string{}
+	var fence codeFence
+	for _, line := range splitLines(original) {
+		if fence.code(line) {
+			continue
+		}
+		if match := standaloneLink.FindStringSubmatch(line); match != nil {
+			links[match[1]+match[2]] = line
+		}
+	}
+	if len(links) == 0 {
+		return body
+	}
+
+	lines := strings.SplitAfter(body, "\n")
+	fence = codeFence{}
+	for i, line := range lines {
+		if fence.code(line) {
+			continue
+		}
+
+		text := strings.TrimRight(line, "\n")
+		match := embedTagPattern.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+
+		if link, ok := links[match[1]]; ok {
+			lines[i] = link + line[len(text):]
+		}
+	}
+
+	return strings.Join(lines, "")
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateLiquidTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected []string
+	}{
+		{
+			"Valid",
+			"{% embed https://go.dev %}\n{% github calvinmclean/article-sync no-readme %}\n{% youtube dQw4w9WgXcQ %}\n" +
+				"{%- tag go -%} and {% user calvinmclean %}\n\n{% details Click to see more %}\nHidden\n{% enddetails %}\n",
+			nil,
+		},
+		{
+			"EditorGuideTags",
+			"{% cta https://example.com %}Subscribe{% endcta %}\n{% card %}\nHighlighted\n{% endcard %}\n" +
+				"{% spoiler Answer %}\n42\n{% endspoiler %}\n{% katex inline %}c = \\pm\\sqrt{a^2 + b^2}{% endkatex %}\n" +
+				"{% twitch ClumsyPrettiestOilLitFam %}\n{% podcast https://dev.to/devdiscuss/episode %}\n{% stackblitz ball-demo %}\n",
+			nil,
+		},
+		{
+			"IgnoresCode",
+			"```liquid\n{% youtub abc %}\n```\n\nUse `{% embed %}` for links\n",
+			nil,
+		},
+		{
+			"UnknownTags",
+			"{% youtub dQw4w9WgXcQ %}\n{% include header.md %}\n",
+			[]string{
+				`1: unknown liquid tag "youtub": did you mean "youtube"?`,
+				`2: unknown liquid tag "include"`,
+			},
+		},
+		{
+			"Arguments",
+			"{% embed %}\n{% embed go.dev %}\n{% youtube dQw4w9WgXcQ extra %}\n{% tag Go %}\n",
+			[]string{
+				"1: {% embed %} needs a URL",
+				`2: {% embed %} needs a URL but got "go.dev"`,
+				"3: {% youtube %} has 2 arguments but accepts at most 1",
+				`4: {% tag %} needs a dev.to tag but got "Go"`,
+			},
+		},
+		{
+			"Blocks",
+			"{% details %}\n{% enddetails %}\n{% endspoiler %}\n{% katex %}\nx^2\n",
+			[]string{
+				"1: {% details %} needs a summary",
+				"3: {% endspoiler %} does not close a {% spoiler %} tag",
+				"4: {% katex %} is never closed with {% endkatex %}",
+			},
+		},
+		{
+			"Raw",
+			"{% raw %}\n{% anything goes %}\n{% endraw %}\n{% embed https://go.dev\n",
+			[]string{"4: liquid tag is not closed with %}"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result []string
+			for _, p := range validateLiquidTags(tt.body) {
+				result = append(result, fmt.Sprintf("%d: %s", p.Line, p.Message))
+			}
+
+			if !slices.Equal(result, tt.expected) {
+				t.Fatalf("unexpected problems:\n%s", strings.Join(result, "\n"))
+			}
+		})
+	}
+}
+
+func TestEmbedLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			"StandaloneLinks",
+			"Intro\n\n[article-sync](https://github.com/calvinmclean/article-sync)\n\n  https://go.dev  \n<https://dev.to>",
+			"Intro\n\n{% embed https://github.com/calvinmclean/article-sync %}\n\n{% embed https://go.dev %}\n{% embed https://dev.to %}",
+		},
+		{
+			"InlineLinksAndCode",
+			"See [the docs](https://go.dev) for more.\n\n```\nhttps://go.dev\n```\n[relative](../other)\n",
+			"See [the docs](https://go.dev) for more.\n\n```\nhttps://go.dev\n```\n[relative](../other)\n",
+		},
+		{
+			"WrittenEmbed",
+			"{% embed https://go.dev %}\nhttps://dev.to\n",
+			"{% embed https://go.dev %}\n{% embed https://dev.to %}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := embedLinks(tt.body)
+			if result != tt.expected {
+				t.Fatalf("unexpected result:\n%s", result)
+			}
+
+			unembedded := unembedLinks(result, tt.body)
+			if unembedded != tt.body {
+				t.Fatalf("expected unembedding to return the original body but got:\n%s", unembedded)
+			}
+		})
+	}
+}
@@ -3,18 +3,20 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"flag"
+	"errors"
 	"fmt"
 	"io/fs"
-	"log"
 	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/calvinmclean/article-sync/api"
-	"github.com/fogleman/gg"
 )
 
 // Article is used to show which fields can read/write to local file
@@ -29,73 +31,43 @@ type Article struct {
 
 	Gopher string `json:"gopher"`
 
-	new     bool
-	updated bool
-}
+	Series     string `json:"series,omitempty"`
+	SeriesPart int    `json:"series_part,omitempty"`
 
-type commentData struct {
-	NewArticles     []*Article
-	UpdatedArticles []*Article
-}
+	Organization string `json:"organization,omitempty"`
 
-func main() {
-	var apiKey, path, prComment, commit, repositoryName, branch string
-	var dryRun, createImage, init bool
-	flag.StringVar(&apiKey, "api-key", "", "API key for accessing dev.to")
-	flag.StringVar(&path, "path", "./articles", "root path to scan for articles")
-	flag.StringVar(&prComment, "pr-comment", "", "file to write the PR comment into")
-	flag.StringVar(&commit, "commit", "", "file to write the commit message into")
-	flag.StringVar(&repositoryName, "repo", "", "repository name. Used for cover image URL")
-	flag.StringVar(&branch, "branch", "", "main branch name. Used for cover image URL")
-	flag.BoolVar(&dryRun, "dry-run", false, "dry-run to print which changes will be made without doing them")
-	flag.BoolVar(&createImage, "create-image", false, "create gopher cover image even if using dry-run")
-	flag.BoolVar(&init, "init", false, "download articles from profile and create directories")
-	flag.Parse()
-
-	if apiKey == "" {
-		apiKey = os.Getenv("API_KEY")
-		if apiKey == "" {
-			log.Fatalf("missing required argument --api-key or env var API_KEY")
-		}
-	}
-
-	client, err := newClient(apiKey, dryRun, createImage)
-	if err != nil {
-		log.Fatalf("error creating API client: %v", err)
-	}
+	// SyncHash is the hash of the contents from the last synchronization
+	SyncHash string `json:"sync_hash,omitempty"`
 
-	if init {
-		err = client.init(path)
-		if err != nil {
-			log.Fatalf("error initializing: %v", err)
-		}
-		return
-	}
+	// Changes and Diff describe how the local article differs from dev.to for the PR comment
+	Changes []string `json:"-"`
+	Diff    string   `json:"-"`
 
-	if branch != "" || repositoryName != "" {
-		client.repositoryName = repositoryName
-		client.branch = branch
-	}
+	new            bool
+	updated        bool
+	organizationID int
+	syncState      syncState
+	reasons        []string
+}
 
-	var data commentData
-	err = client.syncArticlesFromRootDirectory(path, &data)
-	if err != nil {
-		log.Fatalf("error synchronizing directory: %v", err)
-	}
+type commentData struct {
+	NewArticles           []*Article
+	UpdatedArticles       []*Article
+	RemoteChangedArticles []*Article
+	ConflictedArticles    []*Article
 
-	if prComment != "" {
-		err = renderTemplateToFile(prComment, commentTemplate, data)
-		if err != nil {
-			log.Fatalf("error writing PR comment: %v", err)
-		}
-	}
+	// FailedArticles has at most one article unless using keep-going mode since synchronizing stops at the first error
+	FailedArticles []articleResult
 
-	if commit != "" {
-		err = renderTemplateToFile(commit, commitTemplate, data)
-		if err != nil {
-			log.Fatalf("error writing commit: %v", err)
-		}
-	}
+	// Results has every article in the order they were synchronized, for the JSON report
+	Results []articleResult
+
+	// LintFindings are warnings about article markdown, which are only checked when planning
+	LintFindings []lintFinding
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
 }
 
 type client struct {
@@ -104,27 +76,62 @@ type client struct {
 	logger              *slog.Logger
 
 	repositoryName, branch string
+	seriesFooter, force    bool
+	keepGoing, embedLinks  bool
+	render                 *articleRenderer
+
+	// invalidArticles are skipped and reported as failed in keep-going mode. Their problems are already annotated
+	invalidArticles map[string]error
+
+	concurrency int
+	limiter     *rateLimiter
+	retry       retryPolicy
+
+	organization      string
+	organizationIDs   map[string]int
+	organizationIDsMu sync.Mutex
+
+	actions *githubActions
 }
 
-func newClient(apikey string, dryRun, createImage bool) (*client, error) {
-	c, err := api.NewClientWithResponses("https://dev.to", api.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
+func newClient(baseURL, apikey string, dryRun, createImage bool, opts ...api.ClientOption) (*client, error) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	result := &client{
+		dryRun:          dryRun,
+		createImage:     createImage,
+		logger:          logger,
+		concurrency:     1,
+		retry:           defaultRetryPolicy(logger),
+		organizationIDs: map[string]int{},
+		actions:         newGitHubActions(),
+	}
+
+	opts = append(opts, api.WithRequestEditorFn(func(ctx context.Context, req *http.Request) error {
 		req.Header.Add("api-key", apikey)
-		return nil
+		return result.limiter.wait(ctx)
 	}))
+
+	c, err := api.NewClientWithResponses(baseURL, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("error creating client: %w", err)
 	}
+	result.ClientWithResponses = c
 
-	return &client{c, dryRun, createImage, slog.New(slog.NewTextHandler(os.Stdout, nil)), "", ""}, nil
+	return result, nil
 }
 
-func (c *client) init(path string) error {
+func (c *client) init(ctx context.Context, path string) error {
 	err := os.MkdirAll(path, 0755)
 	if err != nil {
 		return fmt.Errorf("error creating directory: %w", err)
 	}
 
-	articles, err := c.getPublishedArticles()
+	var articles []api.ArticleIndex
+	if c.organization != "" {
+		articles, err = c.getOrganizationArticles(ctx, c.organization)
+	} else {
+		articles, err = c.getPublishedArticles(ctx)
+	}
 	if err != nil {
 		return fmt.Errorf("error getting articles: %w", err)
 	}
@@ -137,6 +144,10 @@ func (c *client) init(path string) error {
 	c.logger.Info("existing articles", "count", len(existingArticles))
 
 	for _, a := range articles {
+		if ctx.Err() != nil {
+			return fmt.Errorf("stopped before all articles were created: %w", ctx.Err())
+		}
+
 		logger := c.logger.With("id", a.Id)
 		logger.Info("creating article locally")
 
@@ -146,7 +157,7 @@ func (c *client) init(path string) error {
 			continue
 		}
 
-		fullArticle, err := c.getArticle(int(a.Id))
+		fullArticle, err := c.getArticle(ctx, int(a.Id))
 		if err != nil {
 			logger.Error("error getting article", "error", err)
 			continue
@@ -162,13 +173,19 @@ func (c *client) init(path string) error {
 
 		logger.Info("created directory", "dir", articleDir)
 
+		organization := ""
+		if a.Organization != nil && a.Organization.Username != nil {
+			organization = *a.Organization.Username
+		}
+
 		err = writeArticleFile(articleDir, &Article{
-			ID:          int(a.Id),
-			Slug:        a.Slug,
-			Title:       a.Title,
-			Description: a.Description,
-			URL:         a.Url,
-			Tags:        a.TagList,
+			ID:           int(a.Id),
+			Slug:         a.Slug,
+			Title:        a.Title,
+			Description:  a.Description,
+			URL:          a.Url,
+			Tags:         a.TagList,
+			Organization: organization,
 		})
 		if err != nil {
 			return fmt.Errorf("error writing article JSON file: %w", err)
@@ -190,8 +207,10 @@ func (c *client) init(path string) error {
 	return nil
 }
 
-func (c *client) getExistingArticleIDs(rootDir string) (map[int]struct{}, error) {
-	result := map[int]struct{}{}
+// getExistingArticleIDs maps the ID of each local article to its directory. Articles that are not
+// created yet are not included
+func (c *client) getExistingArticleIDs(rootDir string) (map[int]string, error) {
+	result := map[int]string{}
 
 	err := filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -207,20 +226,16 @@ func (c *client) getExistingArticleIDs(rootDir string) (map[int]struct{}, error)
 		}
 
 		c.logger.Info("checking for article", "directory", path)
-		data, err := os.ReadFile(filepath.Join(path, "article.json"))
-		if err != nil {
-			return fmt.Errorf("error reading JSON file: %w", err)
-		}
-
-		var article *Article
-		err = json.Unmarshal(data, &article)
+		article, err := readArticleFile(path)
 		if err != nil {
-			return fmt.Errorf("error parsing article details: %w", err)
+			return err
 		}
 
 		c.logger.Info("found article", "id", article.ID)
 
-		result[article.ID] = struct{}{}
+		if article.ID != 0 {
+			result[article.ID] = path
+		}
 
 		return nil
 	})
@@ -228,59 +243,255 @@ func (c *client) getExistingArticleIDs(rootDir string) (map[int]struct{}, error)
 	return result, err
 }
 
-func (c *client) syncArticlesFromRootDirectory(rootDir string, data *commentData) error {
-	return filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return fmt.Errorf("error accessing path %s: %w", path, err)
+// syncArticlesFromRootDirectory synchronizes each article directory. By default, it stops at the first
+// error. In keep-going mode, every other article is still synchronized. Failed articles are added to
+// data.FailedArticles and all errors are returned together
+func (c *client) syncArticlesFromRootDirectory(ctx context.Context, rootDir string, data *commentData) error {
+	allDirs, err := findArticleDirectories(rootDir)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	record := func(path string, err error, start time.Time, duration time.Duration) {
+		result := newArticleResult(path, nil, err, start, duration)
+		data.Results = append(data.Results, result)
+		data.FailedArticles = append(data.FailedArticles, result)
+		errs = append(errs, err)
+	}
+	fail := func(path, file string, err error, start time.Time, duration time.Duration) {
+		c.logger.Error("failed to synchronize article", "directory", path, "error", err)
+		c.actions.error(filepath.Join(path, file), err)
+		record(path, err, start, duration)
+	}
+
+	var dirs []string
+	articles := map[string]*Article{}
+	for _, dir := range allDirs {
+		start := time.Now()
+		if err, ok := c.invalidArticles[dir]; ok {
+			c.logger.Error("skipping invalid article", "directory", dir, "error", err)
+			record(dir, fmt.Errorf("invalid article in path %s: %w", dir, err), start, 0)
+			continue
 		}
 
-		if path == rootDir {
-			return nil
+		articles[dir], err = readArticleFile(dir)
+		if err != nil {
+			err = fmt.Errorf("error reading article from path %s: %w", dir, err)
+			fail(dir, "article.json", err, start, time.Since(start))
+			if !c.keepGoing {
+				return err
+			}
+			continue
 		}
+		dirs = append(dirs, dir)
+	}
 
-		if !d.IsDir() {
-			return nil
+	series, err := buildSeriesIndex(dirs, articles)
+	if err != nil {
+		c.actions.error("", err)
+		return fmt.Errorf("invalid series: %w", err)
+	}
+
+	references := c.buildReferenceIndex(dirs, articles)
+
+	order := series.syncOrder(dirs, articles)
+	units := references.syncUnits(series.syncUnits(order, articles))
+	outcomes := c.syncArticlesConcurrently(ctx, units, series)
+
+	// results are collected in the original order so the output doesn't depend on which worker finishes first.
+	// Articles that finished after an error are still included since they might have been created
+	for _, path := range order {
+		outcome, ok := outcomes[path]
+		if !ok {
+			// skipped because synchronization stopped after an error
+			continue
 		}
 
-		c.logger.Info("sychronizing article", "directory", path)
-		article, err := c.syncArticleFromDirectory(path)
-		if err != nil {
-			return fmt.Errorf("error synchronizing article from path %s: %w", path, err)
+		if outcome.err != nil {
+			fail(path, "article.md", fmt.Errorf("error synchronizing article from path %s: %w", path, outcome.err), outcome.start, outcome.duration)
+			continue
 		}
 
+		article := outcome.article
+		data.Results = append(data.Results, newArticleResult(path, article, nil, outcome.start, outcome.duration))
+
 		switch {
 		case article.new:
 			data.NewArticles = append(data.NewArticles, article)
 		case article.updated:
 			data.UpdatedArticles = append(data.UpdatedArticles, article)
+		case article.syncState == syncStateRemoteChange:
+			c.actions.warning(filepath.Join(path, "article.md"), "article was changed on dev.to since the last sync and will not be updated")
+			data.RemoteChangedArticles = append(data.RemoteChangedArticles, article)
+		case article.syncState == syncStateConflict:
+			c.actions.warning(filepath.Join(path, "article.md"), "article was changed locally and on dev.to since the last sync and will not be updated")
+			data.ConflictedArticles = append(data.ConflictedArticles, article)
+		}
+	}
+
+	if ctx.Err() != nil {
+		errs = append(errs, fmt.Errorf("synchronization stopped before all articles were synchronized: %w", ctx.Err()))
+	}
+
+	return errors.Join(errs...)
+}
+
+type syncOutcome struct {
+	article  *Article
+	err      error
+	start    time.Time
+	duration time.Duration
+}
+
+// syncArticlesConcurrently uses a pool of workers to synchronize each unit of articles. Articles in a unit
+// are synchronized in order by the same worker. No more articles are started after the context is done or,
+// unless using keep-going mode, after the first error
+func (c *client) syncArticlesConcurrently(ctx context.Context, units [][]string, series seriesIndex) map[string]syncOutcome {
+	var (
+		mu       sync.Mutex
+		outcomes = map[string]syncOutcome{}
+		stopped  atomic.Bool
+		wg       sync.WaitGroup
+	)
+
+	work := make(chan []string)
+	for i := 0; i < max(c.concurrency, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for unit := range work {
+				for _, path := range unit {
+					if stopped.Load() || ctx.Err() != nil {
+						break
+					}
+
+					c.logger.Info("sychronizing article", "directory", path)
+					start := time.Now()
+					article, err := c.syncArticleFromDirectory(ctx, path, series)
+
+					duration := time.Since(start)
+
+					mu.Lock()
+					outcomes[path] = syncOutcome{article, err, start, duration}
+					mu.Unlock()
+
+					if err != nil && !c.keepGoing {
+						stopped.Store(true)
+					}
+				}
+			}
+		}()
+	}
+
+	for _, unit := range units {
+		if stopped.Load() || ctx.Err() != nil {
+			break
+		}
+		work <- unit
+	}
+	close(work)
+	wg.Wait()
+
+	return outcomes
+}
+
+// findArticleDirectories returns every directory under rootDir, which are all expected to contain an article
+func findArticleDirectories(rootDir string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("error accessing path %s: %w", path, err)
+		}
+
+		if path == rootDir {
+			return nil
+		}
+
+		if !d.IsDir() {
+			return nil
 		}
 
+		dirs = append(dirs, path)
+
 		return nil
 	})
+
+	return dirs, err
+}
+
+// expandedBody reads article.md with templates rendered and snippets included, which is where links to
+// other articles are found
+func (c *client) expandedBody(dir string, article *Article) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "article.md"))
+	if err != nil {
+		return "", fmt.Errorf("error reading markdown: %w", err)
+	}
+
+	markdownBody, err := c.render.render(article, string(data))
+	if err != nil {
+		return "", err
+	}
+
+	markdownBody, _, err = expandIncludes(dir, markdownBody)
+	return markdownBody, err
+}
+
+// publishedBody reads article.md and makes the changes for publishing: rendering templates if enabled,
+// including snippets, resolving links to other articles and, if enabled, embedding standalone links and
+// adding the series footer
+func (c *client) publishedBody(dir string, article *Article, series seriesIndex) (string, error) {
+	markdownBody, err := c.expandedBody(dir, article)
+	if err != nil {
+		return "", err
+	}
+
+	markdownBody, err = resolveReferences(dir, markdownBody)
+	if err != nil {
+		return "", err
+	}
+
+	if c.embedLinks {
+		markdownBody = embedLinks(markdownBody)
+	}
+
+	if c.seriesFooter && article.Series != "" {
+		footer, err := series.footer(dir, article.Series)
+		if err != nil {
+			return "", fmt.Errorf("error creating series footer: %w", err)
+		}
+		markdownBody += footer
+	}
+
+	return markdownBody, nil
 }
 
 // syncArticleFromDirectory will read the article files from a directory and:
 //   - If no ID is provided, create a new article and record ID
 //   - Otherwise, get article by ID and compare text to local text. If the file is
 //     recently changed, it will be updated by API
-func (c *client) syncArticleFromDirectory(dir string) (*Article, error) {
-	markdownBody, err := os.ReadFile(filepath.Join(dir, "article.md"))
+func (c *client) syncArticleFromDirectory(ctx context.Context, dir string, series seriesIndex) (*Article, error) {
+	article, err := readArticleFile(dir)
 	if err != nil {
-		return nil, fmt.Errorf("error reading markdown: %w", err)
+		return nil, err
 	}
 
-	data, err := os.ReadFile(filepath.Join(dir, "article.json"))
+	markdownBody, err := c.publishedBody(dir, article, series)
 	if err != nil {
-		return nil, fmt.Errorf("error reading JSON file: %w", err)
+		return nil, err
 	}
 
-	var article *Article
-	err = json.Unmarshal(data, &article)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing article details: %w", err)
+	logger := c.logger.With("directory", dir).With("title", article.Title)
+
+	if org := c.articleOrganization(article); org != "" {
+		article.organizationID, err = c.getOrganizationID(ctx, org)
+		if err != nil {
+			return nil, fmt.Errorf("error getting organization: %w", err)
+		}
 	}
 
-	logger := c.logger.With("directory", dir).With("title", article.Title)
+	// requests that create or update articles are not canceled by signals so the response can be saved to article.json
+	writeCtx := context.WithoutCancel(ctx)
 
 	var respBody []byte
 	switch article.ID {
@@ -292,18 +503,13 @@ func (c *client) syncArticleFromDirectory(dir string) (*Article, error) {
 			logger.With("gopher", article.Gopher).Info("creating gopher cover image")
 		}
 		if article.Gopher != "" && (c.createImage || !c.dryRun) {
-			coverImg, err := createCoverImage(article.Gopher, article.Title)
-			if err != nil {
-				return nil, fmt.Errorf("error creating cover image: %w", err)
-			}
-
-			err = gg.SavePNG(filepath.Join(dir, "cover_image.png"), coverImg)
+			err = writeCoverImage(ctx, dir, article)
 			if err != nil {
-				return nil, fmt.Errorf("error saving image: %w", err)
+				return nil, err
 			}
 		}
 		img := ""
-		_, err = os.Stat(filepath.Join(dir, "cover_image.png"))
+		_, err = os.Stat(coverImagePath(dir))
 		if err == nil {
 			img = fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s/cover_image.png", c.repositoryName, c.branch, dir)
 			logger.With("url", img).Info("adding image to article")
@@ -313,35 +519,47 @@ func (c *client) syncArticleFromDirectory(dir string) (*Article, error) {
 			return article, nil
 		}
 
-		respBody, err = c.createArticle(article, string(markdownBody), img)
+		respBody, err = c.createArticle(writeCtx, article, markdownBody, img)
 		if err != nil {
 			return nil, fmt.Errorf("error creating article: %w", err)
 		}
 	default:
 		logger = logger.With("id", article.ID)
 
-		shouldUpdate, err := c.shouldUpdateArticle(string(markdownBody), article)
+		article.reasons, err = c.shouldUpdateArticle(ctx, markdownBody, article)
 		if err != nil {
 			return nil, fmt.Errorf("error checking if article needs update: %w", err)
 		}
-		if shouldUpdate == "" {
+		if len(article.reasons) == 0 {
 			logger.Info("article is up-to-date")
+			return article, c.recordSyncHash(dir, article, markdownBody)
+		}
+
+		reason := strings.Join(article.reasons, ", ")
+		if article.syncState != syncStateLocalChange && !c.force {
+			logger.With("reason", reason).With("state", article.syncState).Warn("not updating article that changed on dev.to since the last sync")
 			return article, nil
 		}
-		logger.With("reason", shouldUpdate).Info("updating article")
+
+		logger.With("reason", reason).With("state", article.syncState).Info("updating article")
 		article.updated = true
 
 		if c.dryRun {
 			return article, nil
 		}
 
-		respBody, err = c.updateArticle(dir, article, string(markdownBody))
+		respBody, err = c.updateArticle(writeCtx, dir, article, markdownBody)
 		if err != nil {
 			return nil, fmt.Errorf("error updating article: %w", err)
 		}
 	}
 
-	err = json.Unmarshal(respBody, &article)
+	// the response has the organization as an object, so it is ignored to keep the local username
+	resp := struct {
+		*Article
+		Organization json.RawMessage `json:"organization"`
+	}{Article: article}
+	err = json.Unmarshal(respBody, &resp)
 	if err != nil {
 		return nil, fmt.Errorf("error unmarshaling response JSON: %w", err)
 	}
@@ -353,6 +571,7 @@ func (c *client) syncArticleFromDirectory(dir string) (*Article, error) {
 
 	logger.Info("successfully synchronized article")
 
+	article.SyncHash = syncHash(markdownBody, article.Title, article.Tags)
 	err = writeArticleFile(dir, article)
 	if err != nil {
 		return nil, fmt.Errorf("error writing article JSON file: %w", err)
@@ -361,6 +580,37 @@ func (c *client) syncArticleFromDirectory(dir string) (*Article, error) {
 	return article, nil
 }
 
+// recordSyncHash saves the baseline for an article that is already up-to-date if it is missing or outdated
+func (c *client) recordSyncHash(dir string, article *Article, markdownBody string) error {
+	hash := syncHash(markdownBody, article.Title, article.Tags)
+	if c.dryRun || article.SyncHash == hash {
+		return nil
+	}
+
+	article.SyncHash = hash
+	err := writeArticleFile(dir, article)
+	if err != nil {
+		return fmt.Errorf("error writing article JSON file: %w", err)
+	}
+
+	return nil
+}
+
+func readArticleFile(path string) (*Article, error) {
+	data, err := os.ReadFile(filepath.Join(path, "article.json"))
+	if err != nil {
+		return nil, fmt.Errorf("error reading JSON file: %w", err)
+	}
+
+	var article *Article
+	err = json.Unmarshal(data, &article)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing article details: %w", err)
+	}
+
+	return article, nil
+}
+
 func writeArticleFile(path string, article *Article) error {
 	data, err := json.MarshalIndent(article, "", "    ")
 	if err != nil {
@@ -375,35 +625,107 @@ func writeArticleFile(path string, article *Article) error {
 	return nil
 }
 
-func (c *client) shouldUpdateArticle(markdownBody string, article *Article) (string, error) {
-	articleData, err := c.getArticle(article.ID)
+// articleOrganization returns the organization set in article.json or the default organization
+func (c *client) articleOrganization(article *Article) string {
+	if article.Organization != "" {
+		return article.Organization
+	}
+	return c.organization
+}
+
+// getOrganizationID resolves an organization's username to the ID used when publishing. IDs are cached
+// since most articles will use the same organization
+func (c *client) getOrganizationID(ctx context.Context, username string) (int, error) {
+	c.organizationIDsMu.Lock()
+	defer c.organizationIDsMu.Unlock()
+
+	id, ok := c.organizationIDs[username]
+	if ok {
+		return id, nil
+	}
+
+	id, err := c.getOrganization(ctx, username)
+	if err != nil {
+		return 0, err
+	}
+
+	c.organizationIDs[username] = id
+	return id, nil
+}
+
+func (c *client) shouldUpdateArticle(ctx context.Context, markdownBody string, article *Article) ([]string, error) {
+	articleData, err := c.getArticle(ctx, article.ID)
 	if err != nil {
-		return "", fmt.Errorf("error getting article: %w", err)
+		return nil, fmt.Errorf("error getting article: %w", err)
 	}
 
 	articleMarkdown, ok := articleData["body_markdown"].(string)
 	if !ok {
-		return "", fmt.Errorf("error checking body_markdown")
+		return nil, fmt.Errorf("error checking body_markdown")
 	}
 
 	article.URL, ok = articleData["url"].(string)
 	if !ok {
-		return "", fmt.Errorf("error getting article url")
+		return nil, fmt.Errorf("error getting article url")
 	}
 
+	existingTitle, _ := articleData["title"].(string)
+	existingTags := remoteTags(articleData)
+
+	article.syncState = classifyChange(
+		article.SyncHash,
+		syncHash(markdownBody, article.Title, article.Tags),
+		syncHash(articleMarkdown, existingTitle, existingTags),
+	)
+
+	var reasons []string
 	if articleMarkdown != markdownBody {
-		return "body changed", nil
+		reasons = append(reasons, "body changed")
+		article.Diff = unifiedDiff("dev.to", "article.md", articleMarkdown, markdownBody, maxDiffLines)
 	}
 
-	articleTags := articleData["tags"].([]interface{})
-	existingTags := []string{}
-	for _, tag := range articleTags {
-		existingTags = append(existingTags, tag.(string))
+	if existingTitle != article.Title {
+		reasons = append(reasons, "different title")
+		article.Changes = append(article.Changes, fmt.Sprintf("title: %s → %s", existingTitle, article.Title))
 	}
 
 	if !slices.Equal[[]string, string](existingTags, article.Tags) {
-		return "different tags", nil
+		reasons = append(reasons, "different tags")
+		article.Changes = append(article.Changes, fmt.Sprintf("tags: %s → %s", formatTags(existingTags), formatTags(article.Tags)))
 	}
 
-	return "", nil
+	existingOrganization := ""
+	if org, ok := articleData["organization"].(map[string]interface{}); ok {
+		existingOrganization, _ = org["username"].(string)
+	}
+
+	if existingOrganization != c.articleOrganization(article) {
+		reasons = append(reasons, "different organization")
+		article.Changes = append(article.Changes, fmt.Sprintf("organization: %s → %s", formatOptional(existingOrganization), formatOptional(c.articleOrganization(article))))
+	}
+
+	return reasons, nil
+}
+
+func formatTags(tags []string) string {
+	if len(tags) == 0 {
+		return "none"
+	}
+	return strings.Join(tags, ", ")
+}
+
+func formatOptional(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+func remoteTags(articleData map[string]interface{}) []string {
+	articleTags, _ := articleData["tags"].([]interface{})
+	tags := []string{}
+	for _, tag := range articleTags {
+		tags = append(tags, tag.(string))
+	}
+	return tags
 }
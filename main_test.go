@@ -134,12 +134,118 @@ After merge, 1 new article will be created and 1 existing article will be update
 - new: My New Article (dev.to)
 - updated: My Updated Article (dev.to)`,
 		},
+		{
+			"UpdatedArticleWithChanges",
+			commentData{
+				UpdatedArticles: []*Article{{
+					Title:   "My Updated Article",
+					URL:     "dev.to",
+					Changes: []string{"tags: go → go, testing"},
+					Diff:    "--- dev.to\n+++ article.md\n@@ -1 +1 @@\n-old\n+new\n",
+				}},
+			},
+			`## Article Sync Summary
+
+After merge, 0 new article will be created and 1 existing article will be updated.
+
+### Updated Articles
+- [My Updated Article](dev.to)
+
+<details>
+<summary>Changes to My Updated Article</summary>
+
+- tags: go → go, testing
+
+` + "```" + `diff
+--- dev.to
++++ article.md
+@@ -1 +1 @@
+-old
++new
+` + "```" + `
+
+</details>`,
+			`completed sync: 0 new, 1 updated
+
+- updated: My Updated Article (dev.to)`,
+		},
+		{
+			"RemoteChangesAndConflicts",
+			commentData{
+				RemoteChangedArticles: []*Article{{
+					Title: "My Remote Article",
+					URL:   "dev.to",
+				}},
+				ConflictedArticles: []*Article{{
+					Title: "My Conflicted Article",
+					URL:   "dev.to",
+				}},
+			},
+			`## Article Sync Summary
+
+After merge, 0 new article will be created and 0 existing article will be updated.
+
+### Changed on dev.to
+These articles were edited on dev.to since the last sync and will not be updated. Use ` + "`--pull`" + ` to keep the changes or ` + "`--force`" + ` to overwrite them.
+- [My Remote Article](dev.to)
+
+### Conflicts
+These articles were edited locally and on dev.to since the last sync and will not be updated. Resolve the conflict or use ` + "`--force`" + ` to overwrite the changes on dev.to.
+- [My Conflicted Article](dev.to)`,
+			`completed sync: 0 new, 0 updated
+`,
+		},
+		{
+			"FailedArticles",
+			commentData{
+				NewArticles: []*Article{{
+					Title: "My New Article",
+					URL:   "dev.to",
+				}},
+				FailedArticles: []articleResult{{
+					Directory: "articles/broken",
+					Error:     "error reading markdown",
+				}},
+			},
+			`## Article Sync Summary
+
+After merge, 1 new article will be created and 0 existing article will be updated.
+
+### New Articles
+- My New Article
+
+### Failed
+- articles/broken: error reading markdown`,
+			`completed sync: 1 new, 0 updated, 1 failed
+
+- new: My New Article (dev.to)
+- failed: articles/broken`,
+		},
+		{
+			"LintFindings",
+			commentData{
+				LintFindings: []lintFinding{{
+					File:    "articles/a/article.md",
+					Line:    3,
+					Rule:    lintImageAlt,
+					Message: "image has no alt text",
+				}},
+			},
+			`## Article Sync Summary
+
+After merge, 0 new article will be created and 0 existing article will be updated.
+
+### Lint Warnings
+- articles/a/article.md:3: image has no alt text (image-alt)`,
+			`completed sync: 0 new, 0 updated
+`,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name+"Comment", func(t *testing.T) {
 			var result bytes.Buffer
-			err := renderTemplate(commentTemplate, tt.input, &result)
+			err := renderTemplate(commentTemplate, formatMarkdown, tt.input, &result)
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -150,7 +256,7 @@ After merge, 1 new article will be created and 1 existing article will be update
 		})
 		t.Run(tt.name+"Commit", func(t *testing.T) {
 			var result bytes.Buffer
-			err := renderTemplate(commitTemplate, tt.input, &result)
+			err := renderTemplate(commitTemplate, formatPlainText, tt.input, &result)
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -161,3 +267,152 @@ After merge, 1 new article will be created and 1 existing article will be update
 		})
 	}
 }
+
+func TestTemplateFuncs(t *testing.T) {
+	tests := []struct {
+		name     string
+		tmpl     string
+		input    commentData
+		expected string
+	}{
+		{
+			"PluralizeOne",
+			`{{ len .NewArticles }} new {{ pluralize (len .NewArticles) "article" "articles" }}`,
+			commentData{NewArticles: []*Article{{}}},
+			"1 new article",
+		},
+		{
+			"PluralizeMany",
+			`{{ len .NewArticles }} new {{ pluralize (len .NewArticles) "article" "articles" }}`,
+			commentData{},
+			"0 new articles",
+		},
+		{
+			"Join",
+			`{{ range .NewArticles }}{{ .Tags | join ", " }}{{ end }}`,
+			commentData{NewArticles: []*Article{{Tags: []string{"go", "testing"}}}},
+			"go, testing",
+		},
+		{
+			"Truncate",
+			`{{ range .NewArticles }}{{ .Title | truncate 10 }}{{ end }}`,
+			commentData{NewArticles: []*Article{{Title: "A Very Long Title"}}},
+			"A Very ...",
+		},
+		{
+			"TruncateShort",
+			`{{ range .NewArticles }}{{ .Title | truncate 10 }}{{ end }}`,
+			commentData{NewArticles: []*Article{{Title: "Short"}}},
+			"Short",
+		},
+		{
+			"Date",
+			`{{ now | date "2006" | len }}`,
+			commentData{},
+			"4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result bytes.Buffer
+			err := renderTemplate(tt.tmpl, formatPlainText, tt.input, &result)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.String() != tt.expected {
+				t.Fatalf("unexpected result: %s", result.String())
+			}
+		})
+	}
+}
+
+func TestRenderTemplateParseError(t *testing.T) {
+	err := renderTemplate(`{{ .Missing `, formatPlainText, commentData{}, &bytes.Buffer{})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestTemplateEscaping(t *testing.T) {
+	input := commentData{
+		NewArticles: []*Article{{
+			Title: "Tom's <Go> & [Rust] | C",
+			URL:   "dev.to",
+		}},
+		UpdatedArticles: []*Article{{
+			Title: "A & B",
+			URL:   "dev.to",
+			Diff:  "-a < b\n+a | b\n",
+		}},
+	}
+
+	tests := []struct {
+		name     string
+		tmpl     string
+		format   outputFormat
+		expected string
+	}{
+		{
+			"Commit",
+			commitTemplate,
+			formatPlainText,
+			`completed sync: 1 new, 1 updated
+
+- new: Tom's <Go> & [Rust] | C (dev.to)
+- updated: A & B (dev.to)`,
+		},
+		{
+			"Comment",
+			commentTemplate,
+			formatMarkdown,
+			`## Article Sync Summary
+
+After merge, 1 new article will be created and 1 existing article will be updated.
+
+### New Articles
+- Tom's \<Go\> & \[Rust\] \| C
+
+### Updated Articles
+- [A & B](dev.to)
+
+<details>
+<summary>Changes to A &amp; B</summary>
+
+
+` + "```" + `diff
+-a < b
++a | b
+` + "```" + `
+
+</details>`,
+		},
+		{
+			"CustomCommentTemplate",
+			`{{ range .NewArticles }}| {{ .Title }} | {{ raw .Title }} |{{ end }}`,
+			formatMarkdown,
+			`| Tom's \<Go\> & \[Rust\] \| C | Tom's <Go> & [Rust] | C |`,
+		},
+		{
+			"Assignment",
+			`{{ $count := len .NewArticles }}{{ $count }}`,
+			formatMarkdown,
+			`1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result bytes.Buffer
+			err := renderTemplate(tt.tmpl, tt.format, input, &result)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.String() != tt.expected {
+				t.Fatalf("unexpected result: %s", result.String())
+			}
+		})
+	}
+}
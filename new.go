@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// descriptionPlaceholder is written to article.json by the new command so the description isn't forgotten
+const descriptionPlaceholder = "TODO: describe the article"
+
+// newArticleTemplate is the default article.md for the new command. It has access to the Article
+const newArticleTemplate = `Write the introduction to {{ .Title }} here.
+
+## Section
+
+Write the article here.
+`
+
+var nonSlugCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify creates a directory name from the title using lowercase letters, numbers, and dashes
+func slugify(title string) string {
+	return strings.Trim(nonSlugCharacters.ReplaceAllString(strings.ToLower(title), "-"), "-")
+}
+
+// newArticle creates a directory for a new article under rootDir and returns its path. Empty fields
+// in article use the defaults from the config
+func newArticle(rootDir string, article *Article) (string, error) {
+	slug := slugify(article.Title)
+	if slug == "" {
+		return "", fmt.Errorf("title %q must have at least one letter or number", article.Title)
+	}
+
+	cfg, err := readConfig(rootDir)
+	if err != nil {
+		return "", err
+	}
+
+	if len(article.Tags) == 0 {
+		article.Tags = cfg.Tags
+	}
+	if article.Tags == nil {
+		article.Tags = []string{}
+	}
+	if article.Gopher == "" {
+		article.Gopher = cfg.Gopher
+	}
+	if article.Description == "" {
+		article.Description = descriptionPlaceholder
+	}
+
+	tmplString := newArticleTemplate
+	if cfg.ArticleTemplate != "" {
+		tmplString, err = readTemplate(filepath.Join(rootDir, cfg.ArticleTemplate), newArticleTemplate)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	tmpl, err := template.New("article.md").Funcs(templateFuncs).Parse(tmplString)
+	if err != nil {
+		return "", fmt.Errorf("error parsing article template: %w", err)
+	}
+
+	dir := filepath.Join(rootDir, slug)
+	_, err = os.Stat(dir)
+	if err == nil {
+		return "", fmt.Errorf("directory %s already exists", dir)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("error checking directory: %w", err)
+	}
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", fmt.Errorf("error creating directory: %w", err)
+	}
+
+	f, err := os.Create(filepath.Join(dir, "article.md"))
+	if err != nil {
+		return "", fmt.Errorf("error creating markdown file: %w", err)
+	}
+	defer f.Close()
+
+	err = tmpl.Execute(f, article)
+	if err != nil {
+		return "", fmt.Errorf("error executing article template: %w", err)
+	}
+
+	err = writeArticleFile(dir, article)
+	if err != nil {
+		return "", fmt.Errorf("error writing article JSON file: %w", err)
+	}
+
+	return dir, nil
+}
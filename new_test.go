@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		title    string
+		expected string
+	}{
+		{"My Article", "my-article"},
+		{"  Go 1.22: What's New?  ", "go-1-22-what-s-new"},
+		{"already-a-slug", "already-a-slug"},
+		{"!!!", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			if slug := slugify(tt.title); slug != tt.expected {
+				t.Fatalf("expected %q but got %q", tt.expected, slug)
+			}
+		})
+	}
+}
+
+func TestNewArticle(t *testing.T) {
+	root := t.TempDir()
+	err := os.WriteFile(filepath.Join(root, configFileName), []byte(`{
+    "tags": ["go"],
+    "gopher": "https://example.com/gopher.png",
+    "article_template": "template.md"
+}`), 0640)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = os.WriteFile(filepath.Join(root, "template.md"), []byte("# {{ .Title }}\n"), 0640)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dir, err := newArticle(root, &Article{Title: "My New Article"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dir != filepath.Join(root, "my-new-article") {
+		t.Fatalf("unexpected directory: %s", dir)
+	}
+
+	article, err := readArticleFile(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if article.Title != "My New Article" || article.Description != descriptionPlaceholder ||
+		!slices.Equal(article.Tags, []string{"go"}) || article.Gopher != "https://example.com/gopher.png" {
+		t.Fatalf("unexpected article: %+v", article)
+	}
+
+	body, err := os.ReadFile(filepath.Join(dir, "article.md"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(body) != "# My New Article\n" {
+		t.Fatalf("unexpected body: %q", body)
+	}
+
+	_, err = newArticle(root, &Article{Title: "My New Article!"})
+	if err == nil || err.Error() != "directory "+dir+" already exists" {
+		t.Fatalf("expected error for existing directory but got: %v", err)
+	}
+}
+
+func TestNewArticleWithoutConfig(t *testing.T) {
+	root := t.TempDir()
+
+	dir, err := newArticle(root, &Article{Title: "Article", Tags: []string{"testing"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	article, err := readArticleFile(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(article.Tags, []string{"testing"}) || article.Gopher != "" {
+		t.Fatalf("unexpected article: %+v", article)
+	}
+
+	body, err := os.ReadFile(filepath.Join(dir, "article.md"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(body) != "Write the introduction to Article here.\n\n## Section\n\nWrite the article here.\n" {
+		t.Fatalf("unexpected body: %q", body)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// pull fetches every article that has an ID and overwrites the local files with the remote
+// body, title, description, and tags. New articles without an ID are left alone, and so are articles that use
+// templates and have a changed body unless forced. Links to the URLs of other articles are changed back to
+// relative links, embeds back to the links they were created from, and included snippets that weren't edited
+// back to include directives
+func (c *client) pull(ctx context.Context, rootDir string) error {
+	dirs, err := findArticleDirectories(rootDir)
+	if err != nil {
+		return err
+	}
+
+	urls, err := articleURLs(dirs)
+	if err != nil {
+		return err
+	}
+
+	for _, dir := range dirs {
+		err = c.pullArticleToDirectory(ctx, dir, urls)
+		if err != nil {
+			return fmt.Errorf("error pulling article to path %s: %w", dir, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *client) pullArticleToDirectory(ctx context.Context, dir string, urls map[string]string) error {
+	article, err := readArticleFile(dir)
+	if err != nil {
+		return err
+	}
+
+	logger := c.logger.With("directory", dir).With("title", article.Title)
+	if article.ID == 0 {
+		logger.Info("skipping article that is not published yet")
+		return nil
+	}
+	logger = logger.With("id", article.ID)
+
+	markdownBody, err := os.ReadFile(filepath.Join(dir, "article.md"))
+	if err != nil {
+		return fmt.Errorf("error reading markdown: %w", err)
+	}
+
+	articleData, err := c.getArticle(ctx, article.ID)
+	if err != nil {
+		return fmt.Errorf("error getting article: %w", err)
+	}
+
+	remoteBody, ok := articleData["body_markdown"].(string)
+	if !ok {
+		return fmt.Errorf("error checking body_markdown")
+	}
+
+	remoteMarkdown := remoteBody
+	if c.seriesFooter {
+		remoteMarkdown = stripSeriesFooter(remoteBody)
+	}
+
+	// the local body is compared with templates rendered, snippets included, references resolved, and links
+	// embedded if enabled, so they don't look like changes
+	rendered, err := c.render.render(article, string(markdownBody))
+	if err != nil {
+		return err
+	}
+	localMarkdown, includes, err := expandIncludes(dir, rendered)
+	if err != nil {
+		return err
+	}
+	localMarkdown, err = resolveReferences(dir, localMarkdown)
+	if err != nil {
+		return err
+	}
+	unembedded := localMarkdown
+	if c.embedLinks {
+		localMarkdown = embedLinks(localMarkdown)
+	}
+
+	remote := *article
+	remote.Title, _ = articleData["title"].(string)
+	remote.Description, _ = articleData["description"].(string)
+	remote.Slug, _ = articleData["slug"].(string)
+	remote.URL, _ = articleData["url"].(string)
+	remote.Tags = remoteTags(articleData)
+
+	var changes []string
+	if remoteMarkdown != localMarkdown {
+		changes = append(changes, "body")
+	}
+	if remote.Title != article.Title {
+		changes = append(changes, "title")
+	}
+	if remote.Description != article.Description {
+		changes = append(changes, "description")
+	}
+	if !slices.Equal(remote.Tags, article.Tags) {
+		changes = append(changes, "tags")
+	}
+
+	if len(changes) == 0 {
+		logger.Info("article is up-to-date")
+		return c.recordSyncHash(dir, article, remoteBody)
+	}
+
+	// the pulled body can't be changed back to templates, so they are only replaced when forced
+	if slices.Contains(changes, "body") && rendered != string(markdownBody) && !c.force {
+		logger.Warn("skipping article that uses templates since the pulled body would replace them: use --force to replace them", "changes", changes)
+		return nil
+	}
+
+	logger.Info("pulling remote changes", "changes", changes)
+	if c.dryRun {
+		return nil
+	}
+
+	// the hash uses the remote body since it includes the series footer, like the local body does when synchronizing
+	remote.SyncHash = syncHash(remoteBody, remote.Title, remote.Tags)
+
+	err = os.WriteFile(filepath.Join(dir, "article.md"), []byte(collapseIncludes(unresolveReferences(dir, unembedLinks(remoteMarkdown, unembedded), urls), includes)), 0644)
+	if err != nil {
+		return fmt.Errorf("error writing article markdown file: %w", err)
+	}
+
+	err = writeArticleFile(dir, &remote)
+	if err != nil {
+		return fmt.Errorf("error writing article JSON file: %w", err)
+	}
+
+	logger.Info("updated files", "dir", dir)
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	l := newRateLimiter(100, 2)
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		err := l.wait(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// the first 2 requests use the burst and the next 4 wait 10ms each
+	elapsed := time.Since(start)
+	if elapsed < 35*time.Millisecond {
+		t.Fatalf("expected requests to be limited but took %s", elapsed)
+	}
+}
+
+func TestRateLimiterCancel(t *testing.T) {
+	l := newRateLimiter(0.001, 1)
+
+	err := l.wait(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = l.wait(ctx)
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled but got: %v", err)
+	}
+}
+
+func TestRateLimiterNil(t *testing.T) {
+	var l *rateLimiter
+	err := l.wait(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// replaceLinkTargets calls replace with the target of each link and link reference definition outside of
+// code and uses the result as the new target
+func replaceLinkTargets(body string, replace func(target string) string) string {
+	lines := strings.SplitAfter(body, "\n")
+
+	var fence codeFence
+	for i, line := range lines {
+		if fence.code(line) {
+			continue
+		}
+
+		if loc := referenceLinkPattern.FindStringSubmatchIndex(line); loc != nil {
+			lines[i] = line[:loc[2]] + replace(line[loc[2]:loc[3]]) + line[loc[3]:]
+			continue
+		}
+
+		// inline code is kept as-is
+		var b strings.Builder
+		last := 0
+		for _, code := range inlineCodePattern.FindAllStringIndex(line, -1) {
+			b.WriteString(replaceInlineLinkTargets(line[last:code[0]], replace))
+			b.WriteString(line[code[0]:code[1]])
+			last = code[1]
+		}
+		b.WriteString(replaceInlineLinkTargets(line[last:], replace))
+		lines[i] = b.String()
+	}
+
+	return strings.Join(lines, "")
+}
+
+func replaceInlineLinkTargets(text string, replace func(target string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range inlineLinkPattern.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(text[last:loc[2]])
+		b.WriteString(replace(text[loc[2]:loc[3]]))
+		last = loc[3]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// articleReference returns the directory and anchor of another article linked to with a relative path to its
+// directory or article.md, like ../other-post/article.md#setup
+func articleReference(dir, target string) (string, string, bool) {
+	if target == "" || externalSchemePattern.MatchString(target) || strings.HasPrefix(target, "/") || strings.HasPrefix(target, "#") {
+		return "", "", false
+	}
+
+	path, anchor, _ := strings.Cut(target, "#")
+	path, err := url.PathUnescape(path)
+	if err != nil {
+		return "", "", false
+	}
+
+	targetDir := filepath.Join(dir, filepath.FromSlash(path))
+	if filepath.Base(targetDir) == "article.md" {
+		targetDir = filepath.Dir(targetDir)
+	}
+	if targetDir == filepath.Clean(dir) {
+		return "", "", false
+	}
+
+	_, err = os.Stat(filepath.Join(targetDir, "article.json"))
+	if err != nil {
+		return "", "", false
+	}
+
+	return targetDir, anchor, true
+}
+
+// resolveReferences replaces links to other articles with their published URLs. The URLs are read from disk
+// so articles created earlier in the same run are included. Links to articles that are not published yet
+// are left alone and resolved by a later sync
+func resolveReferences(dir, body string) (string, error) {
+	var err error
+	result := replaceLinkTargets(body, func(target string) string {
+		targetDir, anchor, ok := articleReference(dir, target)
+		if !ok || err != nil {
+			return target
+		}
+
+		var article *Article
+		article, err = readArticleFile(targetDir)
+		if err != nil {
+			err = fmt.Errorf("error reading referenced article %s: %w", targetDir, err)
+			return target
+		}
+
+		switch {
+		case article.URL == "":
+			return target
+		case anchor != "":
+			return article.URL + "#" + anchor
+		}
+		return article.URL
+	})
+
+	return result, err
+}
+
+// unresolveReferences reverses resolveReferences for pulled bodies by replacing the published URLs of
+// articles, from urls, with relative links to their article.md
+func unresolveReferences(dir, body string, urls map[string]string) string {
+	return replaceLinkTargets(body, func(target string) string {
+		path, anchor, hasAnchor := strings.Cut(target, "#")
+		targetDir, ok := urls[path]
+		if !ok || targetDir == filepath.Clean(dir) {
+			return target
+		}
+
+		rel, err := filepath.Rel(dir, targetDir)
+		if err != nil {
+			return target
+		}
+
+		result := filepath.ToSlash(filepath.Join(rel, "article.md"))
+		if hasAnchor {
+			result += "#" + anchor
+		}
+		return result
+	})
+}
+
+// articleURLs maps the published URL of each article to its directory
+func articleURLs(dirs []string) (map[string]string, error) {
+	result := map[string]string{}
+	for _, dir := range dirs {
+		article, err := readArticleFile(dir)
+		if err != nil {
+			return nil, err
+		}
+		if article.URL != "" {
+			result[article.URL] = dir
+		}
+	}
+	return result, nil
+}
+
+// referenceIndex maps an article directory to the directories of the other articles it links to
+type referenceIndex map[string][]string
+
+// buildReferenceIndex finds the links in the same body that is published, so links from partials and
+// included files are found too. Articles that can't be read are left out since synchronizing them fails
+// with the same error
+func (c *client) buildReferenceIndex(dirs []string, articles map[string]*Article) referenceIndex {
+	index := referenceIndex{}
+	for _, dir := range dirs {
+		body, err := c.expandedBody(dir, articles[dir])
+		if err != nil {
+			continue
+		}
+
+		replaceLinkTargets(body, func(target string) string {
+			if targetDir, _, ok := articleReference(dir, target); ok {
+				index[dir] = append(index[dir], targetDir)
+			}
+			return target
+		})
+	}
+	return index
+}
+
+// syncUnits orders the units so articles are synchronized after the articles they link to and can use
+// their URLs. Units that link to each other are merged so one worker synchronizes them in that order.
+// Links in a cycle, including links to later articles in the same series, are resolved by the next sync
+func (r referenceIndex) syncUnits(units [][]string) [][]string {
+	unitOf := map[string]int{}
+	for i, unit := range units {
+		for _, dir := range unit {
+			unitOf[filepath.Clean(dir)] = i
+		}
+	}
+
+	// groups are merged units, tracked by pointing each unit to another unit in its group
+	group := make([]int, len(units))
+	for i := range group {
+		group[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if group[i] != i {
+			group[i] = find(group[i])
+		}
+		return group[i]
+	}
+
+	deps := make([][]int, len(units))
+	for i, unit := range units {
+		for _, dir := range unit {
+			for _, target := range r[dir] {
+				j, ok := unitOf[target]
+				if !ok || j == i {
+					continue
+				}
+				deps[i] = append(deps[i], j)
+				group[find(i)] = find(j)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(units))
+	var ordered []int
+	var visit func(i int)
+	visit = func(i int) {
+		if state[i] != unvisited {
+			return
+		}
+		state[i] = visiting
+		for _, j := range deps[i] {
+			visit(j)
+		}
+		state[i] = visited
+		ordered = append(ordered, i)
+	}
+	for i := range units {
+		visit(i)
+	}
+
+	var result [][]string
+	groupIndex := map[int]int{}
+	for _, i := range ordered {
+		g, ok := groupIndex[find(i)]
+		if !ok {
+			g = len(result)
+			groupIndex[find(i)] = g
+			result = append(result, nil)
+		}
+		result[g] = append(result[g], units[i]...)
+	}
+
+	return result
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestResolveReferences(t *testing.T) {
+	root := t.TempDir()
+	dir := writeTestArticle(t, root, "a", &Article{Title: "A"}, "")
+	writeTestArticle(t, root, "b", &Article{Title: "B", URL: "https://dev.to/tester/b-1"}, "")
+	writeTestArticle(t, root, "new", &Article{Title: "New"}, "")
+
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			"ArticleMarkdown",
+			"Read [B](../b/article.md) first.\n",
+			"Read [B](https://dev.to/tester/b-1) first.\n",
+		},
+		{
+			"DirectoryWithAnchor",
+			"See [setup](../b#setup) and [setup again](../b/ \"B\")\n",
+			"See [setup](https://dev.to/tester/b-1#setup) and [setup again](https://dev.to/tester/b-1 \"B\")\n",
+		},
+		{
+			"ReferenceDefinition",
+			"Read [B][b]\n\n[b]: ../b/article.md\n",
+			"Read [B][b]\n\n[b]: https://dev.to/tester/b-1\n",
+		},
+		{
+			"Unchanged",
+			"[new](../new/article.md) [self](article.md#intro) [missing](../missing) [site](https://go.dev) [anchor](#intro)\n" +
+				"`[code](../b)`\n```md\n[code](../b)\n```\n",
+			"[new](../new/article.md) [self](article.md#intro) [missing](../missing) [site](https://go.dev) [anchor](#intro)\n" +
+				"`[code](../b)`\n```md\n[code](../b)\n```\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := resolveReferences(dir, tt.body)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Fatalf("unexpected result:\n%s", result)
+			}
+		})
+	}
+}
+
+func TestUnresolveReferences(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "a")
+	urls := map[string]string{
+		"https://dev.to/tester/a-1": dir,
+		"https://dev.to/tester/b-1": filepath.Join(root, "b"),
+	}
+
+	body := "[B](https://dev.to/tester/b-1#setup) [self](https://dev.to/tester/a-1) [other](https://dev.to/someone/c)\n\n[b]: https://dev.to/tester/b-1\n"
+	expected := "[B](../b/article.md#setup) [self](https://dev.to/tester/a-1) [other](https://dev.to/someone/c)\n\n[b]: ../b/article.md\n"
+
+	result := unresolveReferences(dir, body, urls)
+	if result != expected {
+		t.Fatalf("unexpected result:\n%s", result)
+	}
+}
+
+func TestReferenceIndexSyncUnits(t *testing.T) {
+	tests := []struct {
+		name       string
+		references referenceIndex
+		units      [][]string
+		expected   [][]string
+	}{
+		{
+			"NoReferences",
+			referenceIndex{},
+			[][]string{{"a"}, {"b"}, {"c"}},
+			[][]string{{"a"}, {"b"}, {"c"}},
+		},
+		{
+			"TargetFirst",
+			referenceIndex{"a": {"c"}},
+			[][]string{{"a"}, {"b"}, {"c"}},
+			[][]string{{"c", "a"}, {"b"}},
+		},
+		{
+			"Chain",
+			referenceIndex{"a": {"b"}, "b": {"c"}},
+			[][]string{{"a"}, {"b"}, {"c"}, {"d"}},
+			[][]string{{"c", "b", "a"}, {"d"}},
+		},
+		{
+			"SeriesUnit",
+			referenceIndex{"s2": {"x"}, "y": {"s1"}},
+			[][]string{{"s1", "s2"}, {"x"}, {"y"}},
+			[][]string{{"x", "s1", "s2", "y"}},
+		},
+		{
+			"Cycle",
+			referenceIndex{"a": {"b"}, "b": {"a"}},
+			[][]string{{"a"}, {"b"}},
+			[][]string{{"b", "a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.references.syncUnits(tt.units)
+			if !slices.EqualFunc(result, tt.expected, slices.Equal[[]string]) {
+				t.Fatalf("unexpected units: %v", result)
+			}
+		})
+	}
+}
+
+func TestBuildReferenceIndex(t *testing.T) {
+	root := t.TempDir()
+	a := writeTestArticle(t, root, "a", &Article{Title: "A"}, "[B](../b/article.md) [C](../c) [site](https://go.dev)")
+	b := writeTestArticle(t, root, "b", &Article{Title: "B"}, "Hello")
+	c := writeTestArticle(t, root, "c", &Article{Title: "C"}, "[A](../a#intro)")
+	d := writeTestArticle(t, root, "d", &Article{Title: "D"}, "{{ partial \"related.md\" }}")
+	e := writeTestArticle(t, root, "e", &Article{Title: "E"}, "{{ .Vars.missing }} [A](../a)")
+
+	err := os.WriteFile(filepath.Join(root, "related.md"), []byte("Read [B](../b) next\n"), 0640)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client := &client{}
+	client.render, err = newArticleRenderer(root, &renderConfig{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dirs := []string{a, b, c, d, e}
+	articles := map[string]*Article{}
+	for _, dir := range dirs {
+		articles[dir], err = readArticleFile(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	index := client.buildReferenceIndex(dirs, articles)
+	if !slices.Equal(index[a], []string{b, c}) || len(index[b]) != 0 || !slices.Equal(index[c], []string{a}) {
+		t.Fatalf("unexpected index: %v", index)
+	}
+	if !slices.Equal(index[d], []string{b}) {
+		t.Fatalf("expected the link in the partial to be indexed but got: %v", index[d])
+	}
+	if len(index[e]) != 0 {
+		t.Fatalf("expected the article that can't be rendered to be left out but got: %v", index[e])
+	}
+}
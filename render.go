@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// renderConfig enables templates in article.md. Since "{{" is common in articles about code, like Go
+// templates, articles are only rendered when it is set and Delims can change the delimiters
+type renderConfig struct {
+	Delims []string       `json:"delims,omitempty"`
+	Vars   map[string]any `json:"vars,omitempty"`
+
+	// Env lists the environment variables available to templates. Others are left out so secrets,
+	// like the API key, can't be published by accident
+	Env []string `json:"env,omitempty"`
+}
+
+// maxPartialDepth stops partials that include themselves
+const maxPartialDepth = 10
+
+// articleRenderer executes article.md as a template with the article, variables from the config, and the
+// allowed environment variables. Partials are files relative to the root directory
+type articleRenderer struct {
+	rootDir string
+	cfg     renderConfig
+	env     map[string]string
+}
+
+// renderData is available in article templates
+type renderData struct {
+	Article *Article
+	Vars    map[string]any
+	Env     map[string]string
+}
+
+// newArticleRenderer returns nil if rendering is not enabled. All methods are safe to use on nil
+func newArticleRenderer(rootDir string, cfg *renderConfig) (*articleRenderer, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	if len(cfg.Delims) != 0 && len(cfg.Delims) != 2 {
+		return nil, errors.New("render delims must have a left and right delimiter")
+	}
+
+	env := map[string]string{}
+	for _, name := range cfg.Env {
+		env[name] = os.Getenv(name)
+	}
+
+	return &articleRenderer{rootDir, *cfg, env}, nil
+}
+
+// loadArticleRenderer creates the renderer from the config in the root directory
+func loadArticleRenderer(rootDir string) (*articleRenderer, error) {
+	cfg, err := readConfig(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	return newArticleRenderer(rootDir, cfg.Render)
+}
+
+// render executes the body as a template. Without a renderer, the body is returned unchanged
+func (r *articleRenderer) render(article *Article, body string) (string, error) {
+	if r == nil {
+		return body, nil
+	}
+
+	return r.execute("article.md", body, renderData{article, r.cfg.Vars, r.env}, 0)
+}
+
+func (r *articleRenderer) execute(name, text string, data renderData, depth int) (string, error) {
+	tmpl := template.New(name).Option("missingkey=error").Funcs(template.FuncMap{
+		// partial renders another file with the same data. A final newline is removed so partials
+		// can be used on their own line without adding a blank line
+		"partial": func(path string) (string, error) {
+			if depth >= maxPartialDepth {
+				return "", fmt.Errorf("partials are nested more than %d levels deep", maxPartialDepth)
+			}
+
+			partialPath := filepath.Join(r.rootDir, filepath.FromSlash(path))
+			if !isWithin(r.rootDir, partialPath) {
+				return "", fmt.Errorf("partial %q is outside of the root directory", path)
+			}
+
+			content, err := os.ReadFile(partialPath)
+			if err != nil {
+				return "", fmt.Errorf("error reading partial: %w", err)
+			}
+
+			result, err := r.execute(path, string(content), data, depth+1)
+			return strings.TrimSuffix(result, "\n"), err
+		},
+	})
+	if len(r.cfg.Delims) == 2 {
+		tmpl = tmpl.Delims(r.cfg.Delims[0], r.cfg.Delims[1])
+	}
+
+	tmpl, err := tmpl.Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("error parsing template: %w", err)
+	}
+
+	var b strings.Builder
+	err = tmpl.Execute(&b, data)
+	if err != nil {
+		return "", fmt.Errorf("error rendering template: %w", err)
+	}
+
+	return b.String(), nil
+}
+
+// isWithin checks that the path doesn't leave the root directory, so files outside of it, like
+// /proc/self/environ, can't be published
+func isWithin(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestArticleRendererRender(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "calvinmclean/article-sync")
+	t.Setenv("API_KEY", "secret")
+
+	root := t.TempDir()
+	err := os.WriteFile(filepath.Join(root, "bio.md"), []byte("Written by {{ .Vars.author }} for {{ .Article.Title }}\n"), 0640)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = os.WriteFile(filepath.Join(root, "footer.md"), []byte("---\n{{ partial \"bio.md\" }}\n"), 0640)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err = os.WriteFile(filepath.Join(root, "loop.md"), []byte(`{{ partial "loop.md" }}`), 0640)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	article := &Article{Title: "My Article", Tags: []string{"go"}}
+	cfg := renderConfig{
+		Vars: map[string]any{"author": "Calvin"},
+		Env:  []string{"GITHUB_REPOSITORY"},
+	}
+
+	tests := []struct {
+		name          string
+		delims        []string
+		body          string
+		expected      string
+		expectedError string
+	}{
+		{
+			"Variables",
+			nil,
+			"# {{ .Article.Title }}\nTagged {{ index .Article.Tags 0 }} in {{ .Env.GITHUB_REPOSITORY }}\n",
+			"# My Article\nTagged go in calvinmclean/article-sync\n",
+			"",
+		},
+		{
+			"Partials",
+			nil,
+			"Hello\n\n{{ partial \"footer.md\" }}\n",
+			"Hello\n\n---\nWritten by Calvin for My Article\n",
+			"",
+		},
+		{
+			"Delims",
+			[]string{"<<", ">>"},
+			"By << .Vars.author >>: `{{ .Name }}`\n",
+			"By Calvin: `{{ .Name }}`\n",
+			"",
+		},
+		{
+			"EnvNotAllowed",
+			nil,
+			"{{ .Env.API_KEY }}",
+			"",
+			`map has no entry for key "API_KEY"`,
+		},
+		{
+			"MissingPartial",
+			nil,
+			`{{ partial "missing.md" }}`,
+			"",
+			"error reading partial",
+		},
+		{
+			"PartialOutsideRoot",
+			nil,
+			`{{ partial "../../../proc/self/environ" }}`,
+			"",
+			`partial "../../../proc/self/environ" is outside of the root directory`,
+		},
+		{
+			"PartialLoop",
+			nil,
+			`{{ partial "loop.md" }}`,
+			"",
+			"partials are nested more than 10 levels deep",
+		},
+		{
+			"ParseError",
+			nil,
+			"Hello\n{{ .Article.Title",
+			"",
+			"error parsing template: template: article.md:2:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := cfg
+			cfg.Delims = tt.delims
+			renderer, err := newArticleRenderer(root, &cfg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			result, err := renderer.render(article, tt.body)
+			if tt.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Fatalf("expected error %q but got: %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Fatalf("unexpected result:\n%s", result)
+			}
+		})
+	}
+}
+
+func TestArticleRendererNil(t *testing.T) {
+	renderer, err := newArticleRenderer(t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	body := "Hello {{ .Name }}"
+	result, err := renderer.render(&Article{}, body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result != body {
+		t.Fatalf("expected body to be unchanged but got %q", result)
+	}
+
+	_, err = newArticleRenderer(t.TempDir(), &renderConfig{Delims: []string{"<<"}})
+	if err == nil {
+		t.Fatal("expected error for one delimiter")
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+type syncAction string
+
+const (
+	syncActionCreate    syncAction = "create"
+	syncActionUpdate    syncAction = "update"
+	syncActionUnchanged syncAction = "unchanged"
+	syncActionSkip      syncAction = "skip"
+	syncActionError     syncAction = "error"
+)
+
+// articleResult records what happened to a single article for the JSON report
+type articleResult struct {
+	Directory  string     `json:"directory"`
+	ID         int        `json:"id,omitempty"`
+	Title      string     `json:"title,omitempty"`
+	Action     syncAction `json:"action"`
+	Reasons    []string   `json:"reasons,omitempty"`
+	URL        string     `json:"url,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	DurationMS int64      `json:"duration_ms"`
+}
+
+// newArticleResult uses the duration measured when the article was synchronized, since results are only
+// created after every article is done
+func newArticleResult(dir string, article *Article, err error, start time.Time, duration time.Duration) articleResult {
+	result := articleResult{
+		Directory:  dir,
+		StartedAt:  start,
+		DurationMS: duration.Milliseconds(),
+	}
+
+	if err != nil {
+		result.Action = syncActionError
+		result.Error = err.Error()
+		return result
+	}
+
+	result.ID = article.ID
+	result.Title = article.Title
+	result.URL = article.URL
+	result.Reasons = article.reasons
+
+	switch {
+	case article.new:
+		result.Action = syncActionCreate
+	case article.updated:
+		result.Action = syncActionUpdate
+	case article.syncState == syncStateRemoteChange || article.syncState == syncStateConflict:
+		result.Action = syncActionSkip
+		result.Reasons = append([]string{string(article.syncState)}, result.Reasons...)
+	default:
+		result.Action = syncActionUnchanged
+	}
+
+	return result
+}
+
+// report is the machine-readable version of the PR comment and commit message. With --dry-run it
+// is the plan of what will happen, otherwise it is the result of the sync
+type report struct {
+	Type       string          `json:"type"`
+	StartedAt  time.Time       `json:"started_at"`
+	DurationMS int64           `json:"duration_ms"`
+	Error      string          `json:"error,omitempty"`
+	Articles   []articleResult `json:"articles"`
+
+	LintFindings []lintFinding `json:"lint_findings,omitempty"`
+}
+
+func writeReport(path string, dryRun bool, start time.Time, data commentData, syncErr error) error {
+	r := report{
+		Type:       "result",
+		StartedAt:  start,
+		DurationMS: time.Since(start).Milliseconds(),
+		Articles:   data.Results,
+
+		LintFindings: data.LintFindings,
+	}
+	if dryRun {
+		r.Type = "plan"
+	}
+	if syncErr != nil {
+		r.Error = syncErr.Error()
+	}
+	if r.Articles == nil {
+		r.Articles = []articleResult{}
+	}
+
+	out, err := json.MarshalIndent(r, "", "    ")
+	if err != nil {
+		return fmt.Errorf("error marshaling report: %w", err)
+	}
+
+	err = os.WriteFile(path, out, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing report file: %w", err)
+	}
+
+	return nil
+}
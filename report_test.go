@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNewArticleResult(t *testing.T) {
+	tests := []struct {
+		name            string
+		article         *Article
+		err             error
+		expectedAction  syncAction
+		expectedReasons []string
+	}{
+		{"Create", &Article{new: true}, nil, syncActionCreate, nil},
+		{"Update", &Article{updated: true, reasons: []string{"body changed"}}, nil, syncActionUpdate, []string{"body changed"}},
+		{"Unchanged", &Article{}, nil, syncActionUnchanged, nil},
+		{
+			"SkipConflict",
+			&Article{syncState: syncStateConflict, reasons: []string{"body changed"}},
+			nil,
+			syncActionSkip,
+			[]string{"conflict", "body changed"},
+		},
+		{"Error", nil, errors.New("oops"), syncActionError, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newArticleResult("dir", tt.article, tt.err, time.Now(), time.Second)
+			if result.Action != tt.expectedAction {
+				t.Fatalf("unexpected action: %s", result.Action)
+			}
+			if !slices.Equal(result.Reasons, tt.expectedReasons) {
+				t.Fatalf("unexpected reasons: %v", result.Reasons)
+			}
+		})
+	}
+}
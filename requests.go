@@ -2,17 +2,17 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
-	"time"
 
 	"github.com/calvinmclean/article-sync/api"
 )
 
-func (c *client) getPublishedArticles() ([]api.ArticleIndex, error) {
-	resp, err := doWithRetry(func() (*api.GetUserPublishedArticlesResponse, error) {
-		return c.GetUserPublishedArticlesWithResponse(context.Background(), nil)
-	}, 5, 1*time.Second)
+func (c *client) getPublishedArticles(ctx context.Context) ([]api.ArticleIndex, error) {
+	resp, err := doWithRetry(ctx, c.retry, idempotent, func(ctx context.Context) (*api.GetUserPublishedArticlesResponse, error) {
+		return c.GetUserPublishedArticlesWithResponse(ctx, nil)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error getting articles: %w", err)
 	}
@@ -24,7 +24,104 @@ func (c *client) getPublishedArticles() ([]api.ArticleIndex, error) {
 	return *resp.JSON200, nil
 }
 
-func (c *client) updateArticle(dir string, article *Article, markdownBody string) ([]byte, error) {
+// userArticle is an article from the listings of the authenticated user's articles. Unlike api.ArticleIndex,
+// it has the markdown body, which these listings include
+type userArticle struct {
+	ID           int      `json:"id"`
+	Slug         string   `json:"slug"`
+	Title        string   `json:"title"`
+	URL          string   `json:"url"`
+	Published    bool     `json:"published"`
+	TagList      []string `json:"tag_list"`
+	BodyMarkdown string   `json:"body_markdown"`
+}
+
+// userArticlesPerPage is the maximum page size allowed by dev.to
+const userArticlesPerPage = 1000
+
+// getUserArticles gets every page of the authenticated user's published or unpublished articles
+func (c *client) getUserArticles(ctx context.Context, published bool) ([]userArticle, error) {
+	var result []userArticle
+	perPage := int32(userArticlesPerPage)
+	for page := int32(1); ; page++ {
+		body, status, err := c.getUserArticlesPage(ctx, published, page, perPage)
+		if err != nil {
+			return nil, fmt.Errorf("error getting articles: %w", err)
+		}
+
+		if status != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status getting articles: %d %s", status, string(body))
+		}
+
+		var articles []userArticle
+		err = json.Unmarshal(body, &articles)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing articles: %w", err)
+		}
+
+		result = append(result, articles...)
+		if len(articles) < userArticlesPerPage {
+			return result, nil
+		}
+	}
+}
+
+func (c *client) getUserArticlesPage(ctx context.Context, published bool, page, perPage int32) ([]byte, int, error) {
+	if published {
+		resp, err := doWithRetry(ctx, c.retry, idempotent, func(ctx context.Context) (*api.GetUserPublishedArticlesResponse, error) {
+			return c.GetUserPublishedArticlesWithResponse(ctx, &api.GetUserPublishedArticlesParams{Page: &page, PerPage: &perPage})
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp.Body, resp.StatusCode(), nil
+	}
+
+	resp, err := doWithRetry(ctx, c.retry, idempotent, func(ctx context.Context) (*api.GetUserUnpublishedArticlesResponse, error) {
+		return c.GetUserUnpublishedArticlesWithResponse(ctx, &api.GetUserUnpublishedArticlesParams{Page: &page, PerPage: &perPage})
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.Body, resp.StatusCode(), nil
+}
+
+func (c *client) getOrganizationArticles(ctx context.Context, username string) ([]api.ArticleIndex, error) {
+	resp, err := doWithRetry(ctx, c.retry, idempotent, func(ctx context.Context) (*api.GetOrgArticlesResponse, error) {
+		return c.GetOrgArticlesWithResponse(ctx, username, nil)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting organization articles: %w", err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status getting organization articles: %d %s", resp.StatusCode(), string(resp.Body))
+	}
+
+	return *resp.JSON200, nil
+}
+
+func (c *client) getOrganization(ctx context.Context, username string) (int, error) {
+	resp, err := doWithRetry(ctx, c.retry, idempotent, func(ctx context.Context) (*api.GetOrganizationResponse, error) {
+		return c.GetOrganizationWithResponse(ctx, username)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error getting organization %s: %w", username, err)
+	}
+
+	if resp.StatusCode() != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status getting organization %s: %d %s", username, resp.StatusCode(), string(resp.Body))
+	}
+
+	id, ok := (*resp.JSON200)["id"].(float64)
+	if !ok {
+		return 0, fmt.Errorf("error getting organization id")
+	}
+
+	return int(id), nil
+}
+
+func (c *client) updateArticle(ctx context.Context, dir string, article *Article, markdownBody string) ([]byte, error) {
 	published := true
 	articleBody := api.Article{}
 	articleBody.Article = &struct {
@@ -38,17 +135,19 @@ func (c *client) updateArticle(dir string, article *Article, markdownBody string
 		Tags           *[]string "json:\"tags,omitempty\""
 		Title          *string   "json:\"title,omitempty\""
 	}{
-		Title:        &article.Title,
-		Description:  &article.Description,
-		BodyMarkdown: &markdownBody,
-		Published:    &published,
-		Tags:         &article.Tags,
-		MainImage:    &article.CoverImage,
+		Title:          &article.Title,
+		Description:    &article.Description,
+		BodyMarkdown:   &markdownBody,
+		Published:      &published,
+		Tags:           &article.Tags,
+		MainImage:      &article.CoverImage,
+		Series:         seriesName(article),
+		OrganizationId: organizationID(article),
 	}
 
-	resp, err := doWithRetry(func() (*api.UpdateArticleResponse, error) {
-		return c.UpdateArticleWithResponse(context.Background(), int32(article.ID), articleBody)
-	}, 5, 1*time.Second)
+	resp, err := doWithRetry(ctx, c.retry, idempotent, func(ctx context.Context) (*api.UpdateArticleResponse, error) {
+		return c.UpdateArticleWithResponse(ctx, int32(article.ID), articleBody)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error updating article: %w", err)
 	}
@@ -60,10 +159,10 @@ func (c *client) updateArticle(dir string, article *Article, markdownBody string
 	return resp.Body, nil
 }
 
-func (c *client) getArticle(id int) (map[string]interface{}, error) {
-	resp, err := doWithRetry(func() (*api.GetArticleByIdResponse, error) {
-		return c.GetArticleByIdWithResponse(context.Background(), id)
-	}, 5, 1*time.Second)
+func (c *client) getArticle(ctx context.Context, id int) (map[string]interface{}, error) {
+	resp, err := doWithRetry(ctx, c.retry, idempotent, func(ctx context.Context) (*api.GetArticleByIdResponse, error) {
+		return c.GetArticleByIdWithResponse(ctx, id)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error getting article %d: %w", id, err)
 	}
@@ -75,7 +174,7 @@ func (c *client) getArticle(id int) (map[string]interface{}, error) {
 	return *resp.JSON200, nil
 }
 
-func (c *client) createArticle(article *Article, body, img string) ([]byte, error) {
+func (c *client) createArticle(ctx context.Context, article *Article, body, img string) ([]byte, error) {
 	published := true
 	articleBody := api.Article{}
 	articleBody.Article = &struct {
@@ -89,17 +188,19 @@ func (c *client) createArticle(article *Article, body, img string) ([]byte, erro
 		Tags           *[]string "json:\"tags,omitempty\""
 		Title          *string   "json:\"title,omitempty\""
 	}{
-		Title:        &article.Title,
-		Description:  &article.Description,
-		BodyMarkdown: &body,
-		Published:    &published,
-		Tags:         &article.Tags,
-		MainImage:    &img,
+		Title:          &article.Title,
+		Description:    &article.Description,
+		BodyMarkdown:   &body,
+		Published:      &published,
+		Tags:           &article.Tags,
+		MainImage:      &img,
+		Series:         seriesName(article),
+		OrganizationId: organizationID(article),
 	}
 
-	resp, err := doWithRetry(func() (*api.CreateArticleResponse, error) {
-		return c.CreateArticleWithResponse(context.Background(), articleBody)
-	}, 5, 1*time.Second)
+	resp, err := doWithRetry(ctx, c.retry, notIdempotent, func(ctx context.Context) (*api.CreateArticleResponse, error) {
+		return c.CreateArticleWithResponse(ctx, articleBody)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error creating article: %w", err)
 	}
@@ -111,24 +212,18 @@ func (c *client) createArticle(article *Article, body, img string) ([]byte, erro
 	return resp.Body, nil
 }
 
-type response interface {
-	StatusCode() int
+// seriesName returns nil when the article is not part of a series so the field is left empty
+func seriesName(article *Article) *string {
+	if article.Series == "" {
+		return nil
+	}
+	return &article.Series
 }
 
-func doWithRetry[T response](f func() (T, error), numRetries int, initialWait time.Duration) (T, error) {
-	for i := 1; i <= numRetries; i++ {
-		result, err := f()
-		if err != nil {
-			return *new(T), err
-		}
-
-		if result.StatusCode() == http.StatusTooManyRequests {
-			time.Sleep(initialWait * time.Duration(i))
-			continue
-		}
-
-		return result, err
+// organizationID returns nil when the article is not published under an organization
+func organizationID(article *Article) *int {
+	if article.organizationID == 0 {
+		return nil
 	}
-
-	return *new(T), fmt.Errorf("exhausted retry limit %d", numRetries)
+	return &article.organizationID
 }
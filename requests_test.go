@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/article-sync/api"
+)
+
+// newRetryTestClient creates a client for the test server that records retry waits instead of sleeping
+func newRetryTestClient(t *testing.T, serverURL string) (*client, *[]time.Duration) {
+	t.Helper()
+
+	apiClient, err := api.NewClientWithResponses(serverURL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var waits []time.Duration
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	policy := defaultRetryPolicy(logger)
+	policy.sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	return &client{
+		ClientWithResponses: apiClient,
+		logger:              logger,
+		retry:               policy,
+	}, &waits
+}
+
+// statusSequence responds with each status in order, then 200 with an article
+func statusSequence(statuses ...int) (http.HandlerFunc, *atomic.Int32) {
+	var calls atomic.Int32
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		if n <= len(statuses) {
+			if statuses[n-1] == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "7")
+			}
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 1, "body_markdown": "body", "url": "dev.to"}`))
+	}, &calls
+}
+
+func TestRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		statuses      []int
+		expectedCalls int32
+		expectedErr   string
+	}{
+		{"Success", nil, 1, ""},
+		{"RetryServiceUnavailable", []int{503, 502, 504}, 4, ""},
+		{"RetryRateLimited", []int{429}, 2, ""},
+		{"NoRetryOnNotFound", []int{404}, 1, "unexpected status getting article 1: 404"},
+		{"ExhaustedRetries", []int{503, 503, 503, 503, 503}, 5, "exhausted retry limit 5: unexpected status 503"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, calls := statusSequence(tt.statuses...)
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			c, _ := newRetryTestClient(t, server.URL)
+			_, err := c.getArticle(context.Background(), 1)
+			switch {
+			case tt.expectedErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.expectedErr != "" && (err == nil || !strings.Contains(err.Error(), tt.expectedErr)):
+				t.Fatalf("expected error %q but got: %v", tt.expectedErr, err)
+			}
+
+			if calls.Load() != tt.expectedCalls {
+				t.Fatalf("expected %d calls but got %d", tt.expectedCalls, calls.Load())
+			}
+		})
+	}
+}
+
+func TestRetryPolicyHonorsRetryAfter(t *testing.T) {
+	handler, _ := statusSequence(http.StatusTooManyRequests)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	c, waits := newRetryTestClient(t, server.URL)
+	_, err := c.getArticle(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(*waits) != 1 || (*waits)[0] != 7*time.Second {
+		t.Fatalf("unexpected waits: %v", *waits)
+	}
+}
+
+func TestRetryPolicyDoesNotRetryCreateOnServerError(t *testing.T) {
+	handler, calls := statusSequence(http.StatusServiceUnavailable)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	c, _ := newRetryTestClient(t, server.URL)
+	_, err := c.createArticle(context.Background(), &Article{Title: "title"}, "body", "")
+	if err == nil || !strings.Contains(err.Error(), "unexpected status creating article: 503") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected 1 call but got %d", calls.Load())
+	}
+}
+
+func TestRetryPolicyRetriesNetworkErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			conn.Close()
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	c, waits := newRetryTestClient(t, server.URL)
+	_, err := c.getArticle(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls.Load() != 2 || len(*waits) != 1 {
+		t.Fatalf("expected 1 retry but got %d calls and waits %v", calls.Load(), *waits)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := retryPolicy{initialWait: time.Second, maxWait: 5 * time.Second}
+
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{1, 500 * time.Millisecond, time.Second},
+		{2, time.Second, 2 * time.Second},
+		{3, 2 * time.Second, 4 * time.Second},
+		{4, 2500 * time.Millisecond, 5 * time.Second},
+		{10, 2500 * time.Millisecond, 5 * time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			wait := policy.backoff(tt.attempt)
+			if wait < tt.min || wait > tt.max {
+				t.Fatalf("attempt %d: wait %s is not between %s and %s", tt.attempt, wait, tt.min, tt.max)
+			}
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"30", 30 * time.Second},
+		{"-1", 0},
+		{"Mon, 01 Jan 2024 00:01:00 GMT", time.Minute},
+		{"Sun, 31 Dec 2023 00:00:00 GMT", 0},
+		{"invalid", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			result := parseRetryAfter(tt.value, now)
+			if result != tt.expected {
+				t.Fatalf("unexpected result: %s", result)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyAttemptTimeout(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			// hang until the attempt times out
+			<-r.Context().Done()
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	c, _ := newRetryTestClient(t, server.URL)
+	c.retry.attemptTimeout = 50 * time.Millisecond
+
+	_, err := c.getArticle(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 calls but got %d", calls.Load())
+	}
+}
+
+func TestRetryPolicyCanceledContext(t *testing.T) {
+	handler, calls := statusSequence(http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c, _ := newRetryTestClient(t, server.URL)
+	c.retry.sleep = func(context.Context, time.Duration) error {
+		cancel()
+		return nil
+	}
+
+	_, err := c.getArticle(ctx, 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled but got: %v", err)
+	}
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected 1 call but got %d", calls.Load())
+	}
+}
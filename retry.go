@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// retryPolicy controls how requests to dev.to are retried. Rate-limited requests are always retried.
+// Server errors and transient network errors are only retried for idempotent requests, since a
+// request that failed that way might have already been applied
+type retryPolicy struct {
+	maxAttempts int
+	initialWait time.Duration
+	maxWait     time.Duration
+	logger      *slog.Logger
+
+	// attemptTimeout limits each attempt so a hung request is retried instead of blocking forever
+	attemptTimeout time.Duration
+
+	// sleep is replaced in tests to check wait times without waiting
+	sleep func(context.Context, time.Duration) error
+}
+
+func defaultRetryPolicy(logger *slog.Logger) retryPolicy {
+	return retryPolicy{
+		maxAttempts: 5,
+		initialWait: 1 * time.Second,
+		maxWait:     60 * time.Second,
+		logger:      logger,
+
+		attemptTimeout: 30 * time.Second,
+		sleep:          sleepContext,
+	}
+}
+
+// sleepContext waits for d or until the context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type idempotency bool
+
+const (
+	idempotent    idempotency = true
+	notIdempotent idempotency = false
+)
+
+// response is implemented by the generated response types. Header is added in the api package
+type response interface {
+	StatusCode() int
+	Header() http.Header
+}
+
+func doWithRetry[T response](ctx context.Context, policy retryPolicy, idempotency idempotency, f func(context.Context) (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := attemptWithTimeout(ctx, policy.attemptTimeout, f)
+
+		var retryErr error
+		var retryAfter time.Duration
+		switch {
+		case err != nil && idempotency == idempotent && isTransientError(err):
+			retryErr = err
+		case err != nil:
+			return result, err
+		case result.StatusCode() == http.StatusTooManyRequests,
+			idempotency == idempotent && isRetryableStatus(result.StatusCode()):
+			retryErr = fmt.Errorf("unexpected status %d", result.StatusCode())
+			retryAfter = parseRetryAfter(result.Header().Get("Retry-After"), time.Now())
+		default:
+			return result, nil
+		}
+
+		// errors caused by the parent context, like the overall timeout, are not retried
+		if ctx.Err() != nil {
+			return *new(T), fmt.Errorf("%w: %w", ctx.Err(), retryErr)
+		}
+
+		if attempt >= policy.maxAttempts {
+			return *new(T), fmt.Errorf("exhausted retry limit %d: %w", policy.maxAttempts, retryErr)
+		}
+
+		wait := policy.backoff(attempt)
+		if retryAfter > 0 {
+			wait = min(retryAfter, policy.maxWait)
+		}
+
+		if policy.logger != nil {
+			policy.logger.Warn("retrying request", "attempt", attempt, "error", retryErr, "wait", wait)
+		}
+		err = policy.sleep(ctx, wait)
+		if err != nil {
+			return *new(T), err
+		}
+	}
+}
+
+// attemptWithTimeout calls f with a timeout. The generated client reads the whole response body
+// before returning, so the context can be canceled right away
+func attemptWithTimeout[T response](ctx context.Context, timeout time.Duration, f func(context.Context) (T, error)) (T, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return f(ctx)
+}
+
+// backoff doubles the wait after each attempt, up to maxWait. Jitter uses a random wait between
+// half and all of the backoff so concurrent workers don't retry at the same time
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	wait := p.initialWait
+	for i := 1; i < attempt && wait < p.maxWait; i++ {
+		wait *= 2
+	}
+	wait = min(wait, p.maxWait)
+
+	half := wait / 2
+	if half <= 0 {
+		return wait
+	}
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isTransientError returns true for network errors that are likely to succeed if the request is retried
+func isTransientError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// parseRetryAfter supports both formats of the Retry-After header: a number of seconds or an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	seconds, err := strconv.Atoi(value)
+	if err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+
+	date, err := http.ParseTime(value)
+	if err == nil {
+		return max(date.Sub(now), 0)
+	}
+
+	return 0
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+const seriesFooterHeading = "**Other posts in this series:**"
+
+// seriesIndex maps a series name to the directories of its articles in series order
+type seriesIndex map[string][]string
+
+// buildSeriesIndex groups the articles by series and validates that every member of
+// a series exists. If parts are used, they must be unique and continuous starting at 1.
+// Otherwise, existing articles are ordered by ID (the order they were published in)
+// followed by new articles in directory order
+func buildSeriesIndex(dirs []string, articles map[string]*Article) (seriesIndex, error) {
+	index := seriesIndex{}
+	for _, dir := range dirs {
+		article := articles[dir]
+		if article.Series == "" {
+			if article.SeriesPart != 0 {
+				return nil, fmt.Errorf("article %s has series_part without series", dir)
+			}
+			continue
+		}
+		index[article.Series] = append(index[article.Series], dir)
+	}
+
+	for name, members := range index {
+		withParts := 0
+		for _, dir := range members {
+			if articles[dir].SeriesPart != 0 {
+				withParts++
+			}
+		}
+
+		switch withParts {
+		case 0:
+			slices.SortStableFunc(members, func(a, b string) int {
+				return compareSeriesIDs(articles[a].ID, articles[b].ID)
+			})
+		case len(members):
+			slices.SortStableFunc(members, func(a, b string) int {
+				return articles[a].SeriesPart - articles[b].SeriesPart
+			})
+			for i, dir := range members {
+				part := articles[dir].SeriesPart
+				if part != i+1 {
+					return nil, fmt.Errorf("series %q is missing part %d (found part %d in %s)", name, i+1, part, dir)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("series %q has %d of %d articles with series_part: all or none must set it", name, withParts, len(members))
+		}
+	}
+
+	return index, nil
+}
+
+// readSeriesIndex reads every article in rootDir to build the series index
+func readSeriesIndex(rootDir string) (seriesIndex, error) {
+	dirs, err := findArticleDirectories(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	articles := map[string]*Article{}
+	for _, dir := range dirs {
+		articles[dir], err = readArticleFile(dir)
+		if err != nil {
+			return nil, fmt.Errorf("error reading article %s: %w", dir, err)
+		}
+	}
+
+	series, err := buildSeriesIndex(dirs, articles)
+	if err != nil {
+		return nil, fmt.Errorf("invalid series: %w", err)
+	}
+
+	return series, nil
+}
+
+// compareSeriesIDs orders existing articles by ID and puts new articles, which have no ID, last
+func compareSeriesIDs(a, b int) int {
+	switch {
+	case a == b:
+		return 0
+	case a == 0:
+		return 1
+	case b == 0:
+		return -1
+	}
+	return a - b
+}
+
+// syncOrder reorders the directories so each series is synchronized in series order. Series
+// members take the positions that the series already occupies, so other articles are unaffected
+func (s seriesIndex) syncOrder(dirs []string, articles map[string]*Article) []string {
+	result := slices.Clone(dirs)
+	next := map[string]int{}
+	for i, dir := range dirs {
+		name := articles[dir].Series
+		if name == "" {
+			continue
+		}
+		result[i] = s[name][next[name]]
+		next[name]++
+	}
+	return result
+}
+
+// syncUnits splits the ordered directories into groups that can be synchronized concurrently. Each series
+// is one unit since its articles must be created in order and each footer uses URLs of earlier articles
+func (s seriesIndex) syncUnits(order []string, articles map[string]*Article) [][]string {
+	var units [][]string
+	seriesUnits := map[string]int{}
+	for _, dir := range order {
+		name := articles[dir].Series
+		if name == "" {
+			units = append(units, []string{dir})
+			continue
+		}
+
+		i, ok := seriesUnits[name]
+		if !ok {
+			i = len(units)
+			seriesUnits[name] = i
+			units = append(units, nil)
+		}
+		units[i] = append(units[i], dir)
+	}
+	return units
+}
+
+// footer creates the "Other posts in this series" section for an article. Sibling articles are
+// read from disk so it includes URLs of articles that were created earlier in the same run.
+// Siblings that are not published yet are left out
+func (s seriesIndex) footer(dir, name string) (string, error) {
+	var links []string
+	for _, siblingDir := range s[name] {
+		if siblingDir == dir {
+			continue
+		}
+
+		sibling, err := readArticleFile(siblingDir)
+		if err != nil {
+			return "", fmt.Errorf("error reading series article %s: %w", siblingDir, err)
+		}
+
+		if sibling.URL == "" {
+			continue
+		}
+
+		links = append(links, fmt.Sprintf("- [%s](%s)", sibling.Title, sibling.URL))
+	}
+
+	if len(links) == 0 {
+		return "", nil
+	}
+
+	return fmt.Sprintf("\n\n---\n\n%s\n%s\n", seriesFooterHeading, strings.Join(links, "\n")), nil
+}
+
+// stripSeriesFooter removes a footer added by footer so it is not saved to the local markdown
+func stripSeriesFooter(body string) string {
+	i := strings.LastIndex(body, "\n\n---\n\n"+seriesFooterHeading+"\n")
+	if i == -1 {
+		return body
+	}
+	return body[:i]
+}
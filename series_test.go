@@ -0,0 +1,175 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSeriesSyncOrder(t *testing.T) {
+	tests := []struct {
+		name          string
+		dirs          []string
+		articles      map[string]*Article
+		expectedOrder []string
+		expectedErr   string
+	}{
+		{
+			"NoSeries",
+			[]string{"a", "b"},
+			map[string]*Article{"a": {}, "b": {}},
+			[]string{"a", "b"},
+			"",
+		},
+		{
+			"OrderedByPart",
+			[]string{"a", "b", "c", "d"},
+			map[string]*Article{
+				"a": {Series: "s", SeriesPart: 3},
+				"b": {},
+				"c": {Series: "s", SeriesPart: 1},
+				"d": {Series: "s", SeriesPart: 2},
+			},
+			[]string{"c", "b", "d", "a"},
+			"",
+		},
+		{
+			"OrderedByIDWithNewArticlesLast",
+			[]string{"a", "b", "c"},
+			map[string]*Article{
+				"a": {Series: "s"},
+				"b": {Series: "s", ID: 20},
+				"c": {Series: "s", ID: 10},
+			},
+			[]string{"c", "b", "a"},
+			"",
+		},
+		{
+			"MissingPart",
+			[]string{"a", "b"},
+			map[string]*Article{
+				"a": {Series: "s", SeriesPart: 1},
+				"b": {Series: "s", SeriesPart: 3},
+			},
+			nil,
+			`series "s" is missing part 2 (found part 3 in b)`,
+		},
+		{
+			"DuplicatePart",
+			[]string{"a", "b"},
+			map[string]*Article{
+				"a": {Series: "s", SeriesPart: 1},
+				"b": {Series: "s", SeriesPart: 1},
+			},
+			nil,
+			`series "s" is missing part 2 (found part 1 in b)`,
+		},
+		{
+			"MixedParts",
+			[]string{"a", "b"},
+			map[string]*Article{
+				"a": {Series: "s", SeriesPart: 1},
+				"b": {Series: "s"},
+			},
+			nil,
+			`series "s" has 1 of 2 articles with series_part: all or none must set it`,
+		},
+		{
+			"PartWithoutSeries",
+			[]string{"a"},
+			map[string]*Article{"a": {SeriesPart: 1}},
+			nil,
+			"article a has series_part without series",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series, err := buildSeriesIndex(tt.dirs, tt.articles)
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Fatalf("expected error %q but got: %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			order := series.syncOrder(tt.dirs, tt.articles)
+			if !slices.Equal(order, tt.expectedOrder) {
+				t.Fatalf("unexpected order: %v", order)
+			}
+		})
+	}
+}
+
+func TestSeriesFooter(t *testing.T) {
+	root := t.TempDir()
+	articles := map[string]*Article{
+		"part-1": {Title: "Part 1", URL: "https://dev.to/part-1", Series: "s", SeriesPart: 1},
+		"part-2": {Title: "Part 2", URL: "https://dev.to/part-2", Series: "s", SeriesPart: 2},
+		"part-3": {Title: "Part 3", Series: "s", SeriesPart: 3},
+	}
+
+	var dirs []string
+	byDir := map[string]*Article{}
+	for name, article := range articles {
+		dir := filepath.Join(root, name)
+		err := os.Mkdir(dir, 0755)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		err = writeArticleFile(dir, article)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		dirs = append(dirs, dir)
+		byDir[dir] = article
+	}
+	slices.Sort(dirs)
+
+	series, err := buildSeriesIndex(dirs, byDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	footer, err := series.footer(filepath.Join(root, "part-1"), "s")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `
+
+---
+
+**Other posts in this series:**
+- [Part 2](https://dev.to/part-2)
+`
+	if footer != expected {
+		t.Fatalf("unexpected result: %s", footer)
+	}
+}
+
+func TestSeriesSyncUnits(t *testing.T) {
+	articles := map[string]*Article{
+		"a": {Series: "s", SeriesPart: 2},
+		"b": {},
+		"c": {Series: "s", SeriesPart: 1},
+		"d": {Series: "t"},
+		"e": {},
+	}
+	dirs := []string{"a", "b", "c", "d", "e"}
+
+	series, err := buildSeriesIndex(dirs, articles)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	units := series.syncUnits(series.syncOrder(dirs, articles), articles)
+	expected := [][]string{{"c", "a"}, {"b"}, {"d"}, {"e"}}
+	if !slices.EqualFunc(units, expected, slices.Equal[[]string]) {
+		t.Fatalf("unexpected units: %v", units)
+	}
+}
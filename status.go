@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"text/tabwriter"
+)
+
+// articleState describes how a local article compares to dev.to
+type articleState string
+
+const (
+	articleStateInSync        articleState = "in-sync"
+	articleStateLocalChange   articleState = "local-change"
+	articleStateRemoteChange  articleState = "remote-change"
+	articleStateConflict      articleState = "conflict"
+	articleStateNew           articleState = "new"
+	articleStateUnpublished   articleState = "unpublished"
+	articleStateMissing       articleState = "missing"
+	articleStateNotDownloaded articleState = "not-downloaded"
+)
+
+// articleStatus is one row of the status command output
+type articleStatus struct {
+	Directory string       `json:"directory,omitempty"`
+	ID        int          `json:"id,omitempty"`
+	Slug      string       `json:"slug,omitempty"`
+	Published bool         `json:"published"`
+	URL       string       `json:"url,omitempty"`
+	State     articleState `json:"state"`
+}
+
+// status compares every local article to the user's published and unpublished articles without changing
+// anything. Published articles that don't have a directory are included at the end as not downloaded
+func (c *client) status(ctx context.Context, rootDir string) ([]articleStatus, error) {
+	dirs, err := findArticleDirectories(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	existingArticles, err := c.getExistingArticleIDs(rootDir)
+	if err != nil {
+		return nil, fmt.Errorf("error getting existing article IDs: %w", err)
+	}
+
+	remoteArticles := map[int]userArticle{}
+	for _, published := range []bool{true, false} {
+		articles, err := c.getUserArticles(ctx, published)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range articles {
+			remoteArticles[a.ID] = a
+		}
+	}
+
+	articles := map[string]*Article{}
+	for _, dir := range dirs {
+		articles[dir], err = readArticleFile(dir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	series, err := buildSeriesIndex(dirs, articles)
+	if err != nil {
+		return nil, fmt.Errorf("error checking series: %w", err)
+	}
+
+	var result []articleStatus
+	for _, dir := range dirs {
+		article := articles[dir]
+		status := articleStatus{
+			Directory: dir,
+			ID:        article.ID,
+			Slug:      article.Slug,
+			URL:       article.URL,
+		}
+
+		remote, ok := remoteArticles[article.ID]
+		switch {
+		case article.ID == 0:
+			status.State = articleStateNew
+		case !ok:
+			status.State = articleStateMissing
+		case !remote.Published:
+			status.Slug = remote.Slug
+			status.State = articleStateUnpublished
+		default:
+			status.Slug, status.URL, status.Published = remote.Slug, remote.URL, true
+			status.State, err = c.compareToRemote(dir, article, remote, series)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		result = append(result, status)
+	}
+
+	var notDownloaded []int
+	for id, remote := range remoteArticles {
+		if _, ok := existingArticles[id]; !ok && remote.Published {
+			notDownloaded = append(notDownloaded, id)
+		}
+	}
+	slices.Sort(notDownloaded)
+
+	for _, id := range notDownloaded {
+		remote := remoteArticles[id]
+		result = append(result, articleStatus{
+			ID:        id,
+			Slug:      remote.Slug,
+			Published: true,
+			URL:       remote.URL,
+			State:     articleStateNotDownloaded,
+		})
+	}
+
+	return result, nil
+}
+
+// compareToRemote uses the same body, title, and tags as synchronizing, including embedded links and the series footer if enabled
+func (c *client) compareToRemote(dir string, article *Article, remote userArticle, series seriesIndex) (articleState, error) {
+	markdownBody, err := c.publishedBody(dir, article, series)
+	if err != nil {
+		return "", err
+	}
+
+	local := syncHash(markdownBody, article.Title, article.Tags)
+	remoteHash := syncHash(remote.BodyMarkdown, remote.Title, remote.TagList)
+	if local == remoteHash {
+		return articleStateInSync, nil
+	}
+
+	switch classifyChange(article.SyncHash, local, remoteHash) {
+	case syncStateRemoteChange:
+		return articleStateRemoteChange, nil
+	case syncStateConflict:
+		return articleStateConflict, nil
+	default:
+		return articleStateLocalChange, nil
+	}
+}
+
+func writeStatusTable(w io.Writer, statuses []articleStatus) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "DIRECTORY\tID\tSLUG\tPUBLISHED\tSTATE\tURL")
+	for _, s := range statuses {
+		id := "-"
+		if s.ID != 0 {
+			id = fmt.Sprint(s.ID)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%t\t%s\t%s\n", formatEmpty(s.Directory), id, formatEmpty(s.Slug), s.Published, s.State, formatEmpty(s.URL))
+	}
+	return tw.Flush()
+}
+
+func writeStatusJSON(w io.Writer, statuses []articleStatus) error {
+	if statuses == nil {
+		statuses = []articleStatus{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	return enc.Encode(statuses)
+}
+
+func formatEmpty(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/calvinmclean/article-sync/fakeforem"
+)
+
+func TestStatus(t *testing.T) {
+	c, forem := newFakeForemClient(t, false)
+	root := t.TempDir()
+
+	for _, name := range []string{"a-in-sync", "b-local", "c-remote", "d-conflict"} {
+		writeTestArticle(t, root, name, &Article{Title: name}, "Hello")
+	}
+	syncTestArticles(t, c, root)
+
+	edit := func(name, body string) {
+		err := os.WriteFile(filepath.Join(root, name, "article.md"), []byte(body), 0640)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	editRemote := func(name, body string) {
+		article, err := readArticleFile(filepath.Join(root, name))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		forem.EditArticle(article.ID, func(a *fakeforem.Article) { a.BodyMarkdown = body })
+	}
+
+	edit("b-local", "Local")
+	editRemote("c-remote", "Remote")
+	edit("d-conflict", "Local")
+	editRemote("d-conflict", "Remote")
+
+	writeTestArticle(t, root, "e-new", &Article{Title: "New"}, "Hello")
+	unpublished := forem.AddArticle(fakeforem.Article{Title: "Draft"})
+	writeTestArticle(t, root, "f-unpublished", &Article{ID: unpublished, Title: "Draft"}, "")
+	writeTestArticle(t, root, "g-missing", &Article{ID: 999, Title: "Deleted"}, "")
+	notDownloaded := forem.AddArticle(fakeforem.Article{Title: "Remote Only", Published: true})
+
+	statuses, err := c.status(context.Background(), root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		dir   string
+		id    int
+		state articleState
+	}{
+		{"a-in-sync", 1, articleStateInSync},
+		{"b-local", 2, articleStateLocalChange},
+		{"c-remote", 3, articleStateRemoteChange},
+		{"d-conflict", 4, articleStateConflict},
+		{"e-new", 0, articleStateNew},
+		{"f-unpublished", unpublished, articleStateUnpublished},
+		{"g-missing", 999, articleStateMissing},
+		{"", notDownloaded, articleStateNotDownloaded},
+	}
+	if len(statuses) != len(expected) {
+		t.Fatalf("expected %d statuses but got %d: %+v", len(expected), len(statuses), statuses)
+	}
+
+	for i, e := range expected {
+		s := statuses[i]
+		dir := ""
+		if e.dir != "" {
+			dir = filepath.Join(root, e.dir)
+		}
+		if s.Directory != dir || s.ID != e.id || s.State != e.state {
+			t.Errorf("unexpected status %d: %+v", i, s)
+		}
+	}
+
+	if statuses[0].URL == "" || !statuses[0].Published {
+		t.Errorf("expected published article to have URL: %+v", statuses[0])
+	}
+}
+
+func TestWriteStatusTable(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeStatusTable(&buf, []articleStatus{
+		{Directory: "articles/a", ID: 1, Slug: "a-1", Published: true, URL: "https://dev.to/user/a-1", State: articleStateInSync},
+		{Directory: "articles/new", State: articleStateNew},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `DIRECTORY     ID  SLUG  PUBLISHED  STATE    URL
+articles/a    1   a-1   true       in-sync  https://dev.to/user/a-1
+articles/new  -   -     false      new      -
+`
+	if buf.String() != expected {
+		t.Fatalf("unexpected result:\n%s", buf.String())
+	}
+}
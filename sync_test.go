@@ -0,0 +1,698 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/calvinmclean/article-sync/api"
+	"github.com/calvinmclean/article-sync/fakeforem"
+)
+
+const testAPIKey = "test-api-key"
+
+// newFakeForemClient creates a test client for a new fake Forem server
+func newFakeForemClient(t *testing.T, dryRun bool) (*client, *fakeforem.Server) {
+	t.Helper()
+
+	forem := fakeforem.New(testAPIKey, "tester")
+	server := forem.Start()
+	t.Cleanup(server.Close)
+
+	return newTestClient(t, server.URL, dryRun), forem
+}
+
+// newTestClient creates a client that doesn't log or sleep between retries
+func newTestClient(t *testing.T, serverURL string, dryRun bool, opts ...api.ClientOption) *client {
+	t.Helper()
+
+	c, err := newClient(serverURL, testAPIKey, dryRun, false, opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	c.retry.logger = c.logger
+	c.retry.sleep = func(context.Context, time.Duration) error { return nil }
+	c.actions = nil
+
+	return c
+}
+
+// writeTestArticle creates an article directory under root and returns its path
+func writeTestArticle(t *testing.T, root, name string, article *Article, body string) string {
+	t.Helper()
+
+	dir := filepath.Join(root, name)
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = writeArticleFile(dir, article)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, "article.md"), []byte(body), 0640)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return dir
+}
+
+func syncTestArticles(t *testing.T, c *client, root string) commentData {
+	t.Helper()
+
+	var data commentData
+	err := c.syncArticlesFromRootDirectory(context.Background(), root, &data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return data
+}
+
+func TestSyncCreateAndUpdate(t *testing.T) {
+	c, forem := newFakeForemClient(t, false)
+	root := t.TempDir()
+	dir := writeTestArticle(t, root, "my-article", &Article{Title: "My Article", Tags: []string{"go"}}, "Hello")
+
+	data := syncTestArticles(t, c, root)
+	if len(data.NewArticles) != 1 {
+		t.Fatalf("expected 1 new article but got %d", len(data.NewArticles))
+	}
+
+	article, err := readArticleFile(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if article.ID == 0 || article.URL == "" || article.SyncHash == "" {
+		t.Fatalf("expected ID, URL, and sync hash to be saved: %+v", article)
+	}
+
+	remote, ok := forem.Article(article.ID)
+	if !ok {
+		t.Fatalf("article %d was not created", article.ID)
+	}
+	if remote.BodyMarkdown != "Hello" || !remote.Published || !slices.Equal(remote.Tags, []string{"go"}) {
+		t.Fatalf("unexpected remote article: %+v", remote)
+	}
+
+	data = syncTestArticles(t, c, root)
+	if len(data.NewArticles) != 0 || len(data.UpdatedArticles) != 0 {
+		t.Fatalf("expected no changes but got %d new and %d updated", len(data.NewArticles), len(data.UpdatedArticles))
+	}
+
+	err = os.WriteFile(filepath.Join(dir, "article.md"), []byte("Hello, World"), 0640)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data = syncTestArticles(t, c, root)
+	if len(data.UpdatedArticles) != 1 {
+		t.Fatalf("expected 1 updated article but got %d", len(data.UpdatedArticles))
+	}
+
+	remote, _ = forem.Article(article.ID)
+	if remote.BodyMarkdown != "Hello, World" {
+		t.Fatalf("unexpected remote body: %q", remote.BodyMarkdown)
+	}
+}
+
+func TestSyncDryRun(t *testing.T) {
+	c, forem := newFakeForemClient(t, true)
+	root := t.TempDir()
+	dir := writeTestArticle(t, root, "my-article", &Article{Title: "My Article"}, "Hello")
+
+	data := syncTestArticles(t, c, root)
+	if len(data.NewArticles) != 1 {
+		t.Fatalf("expected 1 new article but got %d", len(data.NewArticles))
+	}
+
+	if len(forem.Articles()) != 0 {
+		t.Fatalf("expected no articles to be created")
+	}
+
+	article, err := readArticleFile(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if article.ID != 0 {
+		t.Fatalf("expected article.json to be unchanged but got ID %d", article.ID)
+	}
+}
+
+func TestSyncRemoteChange(t *testing.T) {
+	c, forem := newFakeForemClient(t, false)
+	root := t.TempDir()
+	dir := writeTestArticle(t, root, "my-article", &Article{Title: "My Article"}, "Hello")
+	syncTestArticles(t, c, root)
+
+	article, err := readArticleFile(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	forem.EditArticle(article.ID, func(a *fakeforem.Article) {
+		a.BodyMarkdown = "Edited on dev.to"
+	})
+
+	data := syncTestArticles(t, c, root)
+	if len(data.RemoteChangedArticles) != 1 || len(data.UpdatedArticles) != 0 {
+		t.Fatalf("expected 1 remote change and no updates but got %d and %d", len(data.RemoteChangedArticles), len(data.UpdatedArticles))
+	}
+
+	remote, _ := forem.Article(article.ID)
+	if remote.BodyMarkdown != "Edited on dev.to" {
+		t.Fatalf("expected remote change to be kept but got %q", remote.BodyMarkdown)
+	}
+}
+
+func TestSyncOrganization(t *testing.T) {
+	c, forem := newFakeForemClient(t, false)
+	forem.AddOrganization(fakeforem.Organization{ID: 7, Username: "my-org", Name: "My Org"})
+
+	root := t.TempDir()
+	dir := writeTestArticle(t, root, "my-article", &Article{Title: "My Article", Organization: "my-org"}, "Hello")
+	syncTestArticles(t, c, root)
+
+	article, err := readArticleFile(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if article.Organization != "my-org" {
+		t.Fatalf("expected organization to be kept but got %q", article.Organization)
+	}
+
+	remote, _ := forem.Article(article.ID)
+	if remote.OrganizationID != 7 {
+		t.Fatalf("expected organization ID 7 but got %d", remote.OrganizationID)
+	}
+}
+
+func TestSyncEmbedLinks(t *testing.T) {
+	c, forem := newFakeForemClient(t, false)
+	c.embedLinks = true
+
+	root := t.TempDir()
+	dir := writeTestArticle(t, root, "my-article", &Article{Title: "My Article"}, "Hello\n\nhttps://go.dev\n")
+	syncTestArticles(t, c, root)
+
+	article, err := readArticleFile(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	remote, _ := forem.Article(article.ID)
+	if remote.BodyMarkdown != "Hello\n\n{% embed https://go.dev %}\n" {
+		t.Fatalf("unexpected remote body: %q", remote.BodyMarkdown)
+	}
+
+	data := syncTestArticles(t, c, root)
+	if len(data.UpdatedArticles) != 0 || len(data.RemoteChangedArticles) != 0 {
+		t.Fatalf("expected no changes but got %d updated and %d remote changes", len(data.UpdatedArticles), len(data.RemoteChangedArticles))
+	}
+
+	forem.EditArticle(article.ID, func(a *fakeforem.Article) {
+		a.BodyMarkdown = "Hello, World\n\n{% embed https://go.dev %}\n"
+	})
+
+	err = c.pull(context.Background(), root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	body, err := os.ReadFile(filepath.Join(dir, "article.md"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(body) != "Hello, World\n\nhttps://go.dev\n" {
+		t.Fatalf("expected pull to keep the standalone link but got %q", body)
+	}
+
+	data = syncTestArticles(t, c, root)
+	if len(data.UpdatedArticles) != 0 || len(data.RemoteChangedArticles) != 0 {
+		t.Fatalf("expected no changes after pulling but got %d updated and %d remote changes", len(data.UpdatedArticles), len(data.RemoteChangedArticles))
+	}
+}
+
+func TestSyncReferences(t *testing.T) {
+	c, forem := newFakeForemClient(t, false)
+	c.concurrency = 4
+
+	root := t.TempDir()
+	a := writeTestArticle(t, root, "a", &Article{Title: "A"}, "Read [B](../b/article.md#setup) first")
+	b := writeTestArticle(t, root, "b", &Article{Title: "B"}, "## Setup")
+	syncTestArticles(t, c, root)
+
+	articleA, err := readArticleFile(a)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	articleB, err := readArticleFile(b)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	remote, _ := forem.Article(articleA.ID)
+	if remote.BodyMarkdown != "Read [B]("+articleB.URL+"#setup) first" {
+		t.Fatalf("unexpected remote body: %q", remote.BodyMarkdown)
+	}
+
+	data := syncTestArticles(t, c, root)
+	if len(data.UpdatedArticles) != 0 || len(data.RemoteChangedArticles) != 0 {
+		t.Fatalf("expected no changes but got %d updated and %d remote changes", len(data.UpdatedArticles), len(data.RemoteChangedArticles))
+	}
+
+	err = c.pull(context.Background(), root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	body, err := os.ReadFile(filepath.Join(a, "article.md"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(body) != "Read [B](../b/article.md#setup) first" {
+		t.Fatalf("expected pull to keep the relative link but got %q", body)
+	}
+}
+
+func TestSyncConcurrentOrder(t *testing.T) {
+	forem := fakeforem.New(testAPIKey, "tester")
+
+	// requests that arrive first take the longest so articles finish in a different order than they started
+	var arrived atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			time.Sleep(time.Duration(4-(arrived.Add(1)-1)%4) * 20 * time.Millisecond)
+		}
+		forem.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	c := newTestClient(t, server.URL, false)
+	c.concurrency = 4
+
+	root := t.TempDir()
+	titles := []string{"A", "B", "C", "D"}
+	var dirs []string
+	for _, title := range titles {
+		dirs = append(dirs, writeTestArticle(t, root, strings.ToLower(title), &Article{Title: title}, "Hello"))
+	}
+
+	checkOrder := func(t *testing.T, articles []*Article, results []articleResult) {
+		t.Helper()
+
+		var resultTitles, resultDirs []string
+		for _, article := range articles {
+			resultTitles = append(resultTitles, article.Title)
+		}
+		for _, result := range results {
+			resultDirs = append(resultDirs, result.Directory)
+		}
+		if !slices.Equal(resultTitles, titles) {
+			t.Fatalf("expected articles in directory order but got %v", resultTitles)
+		}
+		if !slices.Equal(resultDirs, dirs) {
+			t.Fatalf("expected results in directory order but got %v", resultDirs)
+		}
+	}
+
+	data := syncTestArticles(t, c, root)
+	checkOrder(t, data.NewArticles, data.Results)
+
+	for _, dir := range dirs {
+		err := os.WriteFile(filepath.Join(dir, "article.md"), []byte("Hello, World"), 0640)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	data = syncTestArticles(t, c, root)
+	checkOrder(t, data.UpdatedArticles, data.Results)
+}
+
+func TestSyncConcurrentDurations(t *testing.T) {
+	forem := fakeforem.New(testAPIKey, "tester")
+
+	// only creating the slow article takes long, so the other articles finish while it is still running
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if bytes.Contains(body, []byte(`"title":"Slow"`)) {
+			time.Sleep(200 * time.Millisecond)
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		forem.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	c := newTestClient(t, server.URL, false)
+	c.concurrency = 4
+
+	root := t.TempDir()
+	writeTestArticle(t, root, "a", &Article{Title: "Fast"}, "Hello")
+	writeTestArticle(t, root, "b", &Article{Title: "Slow"}, "Hello")
+	writeTestArticle(t, root, "c", &Article{Title: "Fast"}, "Hello")
+
+	data := syncTestArticles(t, c, root)
+	if len(data.Results) != 3 {
+		t.Fatalf("expected 3 results but got %d", len(data.Results))
+	}
+	for _, result := range data.Results {
+		slow := result.Title == "Slow"
+		if slow != (result.DurationMS >= 200) {
+			t.Fatalf("unexpected duration for %s: %dms", result.Directory, result.DurationMS)
+		}
+	}
+}
+
+func TestSyncIncludes(t *testing.T) {
+	c, forem := newFakeForemClient(t, false)
+	root := testRepository(t)
+	dir := writeTestArticle(t, root, "my-article", &Article{Title: "My Article"}, "Hello\n<!-- include main.go -->\n")
+
+	source := filepath.Join(dir, "main.go")
+	err := os.WriteFile(source, []byte("package main\n"), 0640)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	syncTestArticles(t, c, root)
+
+	article, err := readArticleFile(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	remote, _ := forem.Article(article.ID)
+	if remote.BodyMarkdown != "Hello\n```go\npackage main\n```\n" {
+		t.Fatalf("unexpected remote body: %q", remote.BodyMarkdown)
+	}
+
+	err = os.WriteFile(source, []byte("package example\n"), 0640)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data := syncTestArticles(t, c, root)
+	if len(data.UpdatedArticles) != 1 {
+		t.Fatalf("expected changing the included file to update the article but got %d updated", len(data.UpdatedArticles))
+	}
+
+	remote, _ = forem.Article(article.ID)
+	if remote.BodyMarkdown != "Hello\n```go\npackage example\n```\n" {
+		t.Fatalf("unexpected remote body: %q", remote.BodyMarkdown)
+	}
+}
+
+func TestSyncRender(t *testing.T) {
+	c, forem := newFakeForemClient(t, false)
+	root := t.TempDir()
+	dir := writeTestArticle(t, root, "my-article", &Article{Title: "My Article"}, "Subscribe at {{ .Vars.newsletter }}")
+
+	var err error
+	c.render, err = newArticleRenderer(root, &renderConfig{Vars: map[string]any{"newsletter": "https://example.com/a"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	syncTestArticles(t, c, root)
+
+	article, err := readArticleFile(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	remote, _ := forem.Article(article.ID)
+	if remote.BodyMarkdown != "Subscribe at https://example.com/a" {
+		t.Fatalf("unexpected remote body: %q", remote.BodyMarkdown)
+	}
+
+	c.render, err = newArticleRenderer(root, &renderConfig{Vars: map[string]any{"newsletter": "https://example.com/b"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data := syncTestArticles(t, c, root)
+	if len(data.UpdatedArticles) != 1 {
+		t.Fatalf("expected changing the variable to update the article but got %d updated", len(data.UpdatedArticles))
+	}
+}
+
+func TestPullRender(t *testing.T) {
+	c, forem := newFakeForemClient(t, false)
+	root := t.TempDir()
+	dir := writeTestArticle(t, root, "my-article", &Article{Title: "My Article"}, "Subscribe at {{ .Vars.newsletter }}")
+
+	var err error
+	c.render, err = newArticleRenderer(root, &renderConfig{Vars: map[string]any{"newsletter": "https://example.com"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	syncTestArticles(t, c, root)
+
+	article, err := readArticleFile(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	forem.EditArticle(article.ID, func(a *fakeforem.Article) {
+		a.BodyMarkdown = "Subscribe at https://example.com today"
+	})
+
+	tests := []struct {
+		name     string
+		force    bool
+		expected string
+	}{
+		{"KeepTemplates", false, "Subscribe at {{ .Vars.newsletter }}"},
+		{"Force", true, "Subscribe at https://example.com today"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c.force = tt.force
+			err := c.pull(context.Background(), root)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			body, err := os.ReadFile(filepath.Join(dir, "article.md"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(body) != tt.expected {
+				t.Fatalf("expected article.md %q but got %q", tt.expected, body)
+			}
+		})
+	}
+}
+
+func TestSyncRetriesRateLimit(t *testing.T) {
+	c, forem := newFakeForemClient(t, false)
+	forem.InjectFault(fakeforem.Fault{Method: "POST", Path: "/api/articles", Status: 429, RetryAfter: "1", Times: 2})
+
+	root := t.TempDir()
+	writeTestArticle(t, root, "my-article", &Article{Title: "My Article"}, "Hello")
+	syncTestArticles(t, c, root)
+
+	expected := []string{"POST /api/articles", "POST /api/articles", "POST /api/articles"}
+	if !slices.Equal(forem.Requests(), expected) {
+		t.Fatalf("unexpected requests: %v", forem.Requests())
+	}
+	if len(forem.Articles()) != 1 {
+		t.Fatalf("expected 1 article but got %d", len(forem.Articles()))
+	}
+}
+
+func TestSyncError(t *testing.T) {
+	c, forem := newFakeForemClient(t, false)
+	forem.InjectFault(fakeforem.Fault{Method: "POST", Status: 422, Body: "Title can't be blank"})
+
+	root := t.TempDir()
+	writeTestArticle(t, root, "my-article", &Article{Title: "My Article"}, "Hello")
+
+	var data commentData
+	err := c.syncArticlesFromRootDirectory(context.Background(), root, &data)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if len(data.FailedArticles) != 1 {
+		t.Fatalf("expected 1 failed article but got %d", len(data.FailedArticles))
+	}
+}
+
+func TestSyncKeepGoing(t *testing.T) {
+	c, forem := newFakeForemClient(t, false)
+	c.keepGoing = true
+	forem.InjectFault(fakeforem.Fault{Method: "POST", Status: 422, Body: "Title can't be blank", Times: 1})
+
+	root := t.TempDir()
+	failed := writeTestArticle(t, root, "a", &Article{Title: "A"}, "Hello")
+	passed := writeTestArticle(t, root, "b", &Article{Title: "B"}, "Hello")
+
+	var data commentData
+	err := c.syncArticlesFromRootDirectory(context.Background(), root, &data)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	var posts int
+	for _, r := range forem.Requests() {
+		if r == "POST /api/articles" {
+			posts++
+		}
+	}
+	if posts != 2 {
+		t.Fatalf("expected both articles to be attempted but got %d requests: %v", posts, forem.Requests())
+	}
+
+	if len(data.FailedArticles) != 1 || data.FailedArticles[0].Directory != failed {
+		t.Fatalf("expected %s to fail but got %+v", failed, data.FailedArticles)
+	}
+
+	article, err := readArticleFile(passed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if article.ID == 0 {
+		t.Fatal("expected the article after the failure to be created with an ID")
+	}
+
+	article, err = readArticleFile(failed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if article.ID != 0 {
+		t.Fatalf("expected the failed article to have no ID but got %d", article.ID)
+	}
+}
+
+func TestPull(t *testing.T) {
+	tests := []struct {
+		name   string
+		dryRun bool
+	}{
+		{"Write", false},
+		{"DryRun", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, forem := newFakeForemClient(t, false)
+			root := t.TempDir()
+			dir := writeTestArticle(t, root, "my-article", &Article{Title: "My Article", Tags: []string{"go"}}, "Hello")
+			syncTestArticles(t, c, root)
+
+			before, err := readArticleFile(dir)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			forem.EditArticle(before.ID, func(a *fakeforem.Article) {
+				a.BodyMarkdown = "Edited on dev.to"
+				a.Title = "Edited Title"
+				a.Tags = []string{"go", "testing"}
+			})
+
+			c.dryRun = tt.dryRun
+			err = c.pull(context.Background(), root)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			body, err := os.ReadFile(filepath.Join(dir, "article.md"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			after, err := readArticleFile(dir)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.dryRun {
+				if string(body) != "Hello" {
+					t.Fatalf("expected dry run to keep article.md but got %q", body)
+				}
+				if after.Title != before.Title || !slices.Equal(after.Tags, before.Tags) || after.SyncHash != before.SyncHash {
+					t.Fatalf("expected dry run to keep article.json but got %+v", after)
+				}
+				return
+			}
+
+			if string(body) != "Edited on dev.to" {
+				t.Fatalf("expected pulled body but got %q", body)
+			}
+			if after.Title != "Edited Title" {
+				t.Fatalf("expected pulled title but got %q", after.Title)
+			}
+			if !slices.Equal(after.Tags, []string{"go", "testing"}) {
+				t.Fatalf("expected pulled tags but got %v", after.Tags)
+			}
+			if after.SyncHash != syncHash("Edited on dev.to", "Edited Title", after.Tags) {
+				t.Fatalf("expected sync hash of the pulled article but got %q", after.SyncHash)
+			}
+
+			data := syncTestArticles(t, c, root)
+			if len(data.UpdatedArticles) != 0 || len(data.RemoteChangedArticles) != 0 {
+				t.Fatalf("expected no changes after pulling but got %d updated and %d remote changes", len(data.UpdatedArticles), len(data.RemoteChangedArticles))
+			}
+		})
+	}
+}
+
+func TestInit(t *testing.T) {
+	c, forem := newFakeForemClient(t, false)
+	forem.AddArticle(fakeforem.Article{Title: "First", BodyMarkdown: "one", Tags: []string{"go"}, Published: true})
+	forem.AddArticle(fakeforem.Article{Title: "Second", BodyMarkdown: "two", Published: true})
+	forem.AddArticle(fakeforem.Article{Title: "Draft", BodyMarkdown: "draft"})
+
+	root := t.TempDir()
+	err := c.init(context.Background(), root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	dirs, err := findArticleDirectories(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(dirs) != 2 {
+		t.Fatalf("expected 2 articles but got %v", dirs)
+	}
+
+	first, _ := forem.Article(1)
+	article, err := readArticleFile(filepath.Join(root, first.Slug))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if article.ID != 1 || article.Title != "First" || !slices.Equal(article.Tags, []string{"go"}) {
+		t.Fatalf("unexpected article: %+v", article)
+	}
+
+	body, err := os.ReadFile(filepath.Join(root, first.Slug, "article.md"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(body) != "one" {
+		t.Fatalf("unexpected body: %q", body)
+	}
+
+	// the imported articles are up-to-date
+	data := syncTestArticles(t, c, root)
+	if len(data.NewArticles) != 0 || len(data.UpdatedArticles) != 0 {
+		t.Fatalf("expected no changes but got %d new and %d updated", len(data.NewArticles), len(data.UpdatedArticles))
+	}
+}
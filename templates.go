@@ -2,9 +2,13 @@ package main
 
 import (
 	"fmt"
-	"html/template"
+	"html"
 	"io"
 	"os"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
 )
 
 const (
@@ -25,26 +29,106 @@ After merge, {{ len .NewArticles }} new article will be created and {{ len .Upda
 {{- range .UpdatedArticles }}
 - [{{ .Title }}]({{ .URL }})
 {{- end }}
+{{- range .UpdatedArticles }}
+{{- if or .Changes .Diff }}
+
+<details>
+<summary>Changes to {{ htmlText .Title }}</summary>
+{{ range .Changes }}
+- {{ . }}
+{{- end }}
+{{- if .Diff }}
+
+{{ codeBlock "diff" .Diff }}
+{{- end }}
+
+</details>
+{{- end }}
+{{- end }}
+{{- end }}
+{{- if gt (len .RemoteChangedArticles) 0 }}
+
+### Changed on dev.to
+These articles were edited on dev.to since the last sync and will not be updated. Use ` + "`--pull`" + ` to keep the changes or ` + "`--force`" + ` to overwrite them.
+{{- range .RemoteChangedArticles }}
+- [{{ .Title }}]({{ .URL }})
+{{- end }}
+{{- end }}
+{{- if gt (len .ConflictedArticles) 0 }}
+
+### Conflicts
+These articles were edited locally and on dev.to since the last sync and will not be updated. Resolve the conflict or use ` + "`--force`" + ` to overwrite the changes on dev.to.
+{{- range .ConflictedArticles }}
+- [{{ .Title }}]({{ .URL }})
+{{- end }}
+{{- end }}
+{{- if gt (len .FailedArticles) 0 }}
+
+### Failed
+{{- range .FailedArticles }}
+- {{ .Directory }}: {{ .Error }}
+{{- end }}
+{{- end }}
+{{- if gt (len .LintFindings) 0 }}
+
+### Lint Warnings
+{{- range .LintFindings }}
+- {{ .File }}:{{ .Line }}: {{ .Message }} ({{ .Rule }})
+{{- end }}
 {{- end }}`
 
-	commitTemplate = `completed sync: {{ len .NewArticles }} new, {{ len .UpdatedArticles }} updated
+	commitTemplate = `completed sync: {{ len .NewArticles }} new, {{ len .UpdatedArticles }} updated{{ if gt (len .FailedArticles) 0 }}, {{ len .FailedArticles }} failed{{ end }}
 {{ if or (gt (len .NewArticles) 0) (gt (len .UpdatedArticles) 0) }}{{ end }}
 {{- range .NewArticles }}
 - new: {{ .Title }} ({{ .URL }})
 {{- end }}
 {{- range .UpdatedArticles }}
 - updated: {{ .Title }} ({{ .URL }})
+{{- end }}
+{{- range .FailedArticles }}
+- failed: {{ .Directory }}
 {{- end }}`
 )
 
-func renderTemplateToFile(path, tmpl string, data commentData) error {
+// outputFormat controls how values are escaped when rendering a template
+type outputFormat int
+
+const (
+	// formatPlainText does not escape anything, which is used for commit messages
+	formatPlainText outputFormat = iota
+	// formatMarkdown escapes characters that change the meaning of markdown, which is used for PR comments
+	formatMarkdown
+)
+
+// markdown is text that is already safe to use in markdown, so it is not escaped again
+type markdown string
+
+// markdownEscaper escapes characters that would create links, tables, code, or HTML in markdown
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`[`, `\[`,
+	`]`, `\]`,
+	`|`, `\|`,
+	`<`, `\<`,
+	`>`, `\>`,
+)
+
+func escapeMarkdown(v any) markdown {
+	if md, ok := v.(markdown); ok {
+		return md
+	}
+	return markdown(markdownEscaper.Replace(fmt.Sprint(v)))
+}
+
+func renderTemplateToFile(path, tmpl string, format outputFormat, data commentData) error {
 	file, err := os.Create(path)
 	if err != nil {
 		return fmt.Errorf("error creating file: %w", err)
 	}
 	defer file.Close()
 
-	err = renderTemplate(tmpl, data, file)
+	err = renderTemplate(tmpl, format, data, file)
 	if err != nil {
 		return fmt.Errorf("error writing to file: %w", err)
 	}
@@ -52,13 +136,139 @@ func renderTemplateToFile(path, tmpl string, data commentData) error {
 	return nil
 }
 
-func renderTemplate(tmplString string, data commentData, destination io.Writer) error {
-	tmpl := template.Must(template.New("tmpl").Parse(tmplString))
+// readTemplate returns the contents of a user-supplied template file, or the default if no file is provided
+func readTemplate(path, defaultTemplate string) (string, error) {
+	if path == "" {
+		return defaultTemplate, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading template file: %w", err)
+	}
+
+	return string(data), nil
+}
+
+var templateFuncs = template.FuncMap{
+	"codeBlock":      codeBlock,
+	"htmlText":       htmlText,
+	"raw":            raw,
+	"pluralize":      pluralize,
+	"join":           join,
+	"date":           date,
+	"now":            time.Now,
+	"truncate":       truncate,
+	"escapeMarkdown": escapeMarkdown,
+}
 
-	err := tmpl.Execute(destination, data)
+func renderTemplate(tmplString string, format outputFormat, data commentData, destination io.Writer) error {
+	tmpl, err := template.New("tmpl").Funcs(templateFuncs).Parse(tmplString)
+	if err != nil {
+		return fmt.Errorf("error parsing template: %w", err)
+	}
+
+	if format == formatMarkdown {
+		for _, t := range tmpl.Templates() {
+			if t.Tree != nil {
+				escapeActions(t.Tree, t.Tree.Root)
+			}
+		}
+	}
+
+	err = tmpl.Execute(destination, data)
 	if err != nil {
 		return fmt.Errorf("error executing template: %w", err)
 	}
 
 	return nil
 }
+
+// escapeActions adds escapeMarkdown to the end of every pipeline that writes output, similar to
+// how html/template escapes output, so user-supplied templates are escaped without extra effort
+func escapeActions(tree *parse.Tree, node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			escapeActions(tree, child)
+		}
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) > 0 {
+			return
+		}
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args:     []parse.Node{parse.NewIdentifier("escapeMarkdown").SetTree(tree).SetPos(n.Pos)},
+		})
+	case *parse.IfNode:
+		escapeActions(tree, n.List)
+		escapeActions(tree, n.ElseList)
+	case *parse.RangeNode:
+		escapeActions(tree, n.List)
+		escapeActions(tree, n.ElseList)
+	case *parse.WithNode:
+		escapeActions(tree, n.List)
+		escapeActions(tree, n.ElseList)
+	}
+}
+
+// codeBlock wraps text in a fenced code block using a fence that is longer than any backtick
+// sequence in the text, since article markdown often contains code blocks of its own
+func codeBlock(lang, text string) markdown {
+	longest, current := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			current++
+			longest = max(longest, current)
+		} else {
+			current = 0
+		}
+	}
+
+	fence := strings.Repeat("`", max(3, longest+1))
+	return markdown(fmt.Sprintf("%s%s\n%s%s", fence, lang, text, fence))
+}
+
+// htmlText escapes text used inside of HTML tags, like <summary>, where markdown escapes are shown literally
+func htmlText(s string) markdown {
+	return markdown(html.EscapeString(s))
+}
+
+// raw allows custom templates to write values without escaping them: {{ raw .Diff }}
+func raw(s string) markdown {
+	return markdown(s)
+}
+
+// pluralize returns singular if count is 1, otherwise plural: {{ pluralize (len .NewArticles) "article" "articles" }}
+func pluralize(count int, singular, plural string) string {
+	if count == 1 {
+		return singular
+	}
+	return plural
+}
+
+// join has the separator first so it can be used in a pipeline: {{ .Tags | join ", " }}
+func join(sep string, elems []string) string {
+	return strings.Join(elems, sep)
+}
+
+// date formats a time using a Go layout: {{ now | date "2006-01-02" }}
+func date(layout string, t time.Time) string {
+	return t.Format(layout)
+}
+
+// truncate shortens s to at most length characters, ending with "..." if it was shortened: {{ .Title | truncate 40 }}
+func truncate(length int, s string) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+	if length <= 3 {
+		return string(runes[:length])
+	}
+	return string(runes[:length-3]) + "..."
+}
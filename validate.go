@@ -0,0 +1,359 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// articleSchema is the JSON Schema for article.json. It can also be used by editors to check files as they are written
+//
+//go:embed article.schema.json
+var articleSchema []byte
+
+// jsonSchema is the subset of JSON Schema used by article.schema.json
+type jsonSchema struct {
+	Type                 schemaTypes            `json:"type"`
+	Required             []string               `json:"required"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	MaxItems             *int                   `json:"maxItems"`
+	UniqueItems          bool                   `json:"uniqueItems"`
+	MinLength            *int                   `json:"minLength"`
+	MaxLength            *int                   `json:"maxLength"`
+	Pattern              string                 `json:"pattern"`
+	Minimum              *float64               `json:"minimum"`
+
+	// annotations are allowed but don't change validation
+	Schema      string `json:"$schema"`
+	ID          string `json:"$id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+
+	pattern *regexp.Regexp
+}
+
+// schemaTypes is the type keyword, which is a single type or a list of allowed types
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if json.Unmarshal(data, &single) == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// parseSchema parses the schema and compiles its patterns. Keywords that aren't supported are errors
+// instead of being ignored, so the schema can't silently allow more than it says
+func parseSchema(data []byte) (*jsonSchema, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var schema jsonSchema
+	err := decoder.Decode(&schema)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing schema: %w", err)
+	}
+
+	err = schema.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	return &schema, nil
+}
+
+func (s *jsonSchema) compile() error {
+	if s.Pattern != "" {
+		var err error
+		s.pattern, err = regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("error compiling pattern %q: %w", s.Pattern, err)
+		}
+	}
+
+	for _, property := range s.Properties {
+		err := property.compile()
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.Items != nil {
+		return s.Items.compile()
+	}
+
+	return nil
+}
+
+// validate returns every problem with the value. Each problem starts with the path of the invalid field,
+// except for problems with the root value which has an empty path
+func (s *jsonSchema) validate(path string, value any) []string {
+	var problems []string
+	problem := func(format string, args ...any) {
+		msg := fmt.Sprintf(format, args...)
+		if path != "" {
+			msg = path + ": " + msg
+		}
+		problems = append(problems, msg)
+	}
+
+	if !s.allowsType(jsonType(value)) {
+		problem("must be %s but is %s", strings.Join(s.Type, " or "), jsonType(value))
+		return problems
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				problem("missing required field %q", name)
+			}
+		}
+
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			property, ok := s.Properties[name]
+			switch {
+			case ok:
+				problems = append(problems, property.validate(joinSchemaPath(path, name), v[name])...)
+			case s.AdditionalProperties != nil && !*s.AdditionalProperties:
+				problem("unknown field %q", name)
+			}
+		}
+	case []any:
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			problem("must have at most %d items but has %d", *s.MaxItems, len(v))
+		}
+
+		for i, item := range v {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			if s.UniqueItems && slices.IndexFunc(v[:i], func(other any) bool { return fmt.Sprint(other) == fmt.Sprint(item) }) != -1 {
+				problems = append(problems, fmt.Sprintf("%s: duplicate item %q", itemPath, fmt.Sprint(item)))
+			}
+			if s.Items != nil {
+				problems = append(problems, s.Items.validate(itemPath, item)...)
+			}
+		}
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.MinLength != nil && length < *s.MinLength {
+			if *s.MinLength == 1 {
+				problem("must not be empty")
+			} else {
+				problem("must be at least %d characters but is %d", *s.MinLength, length)
+			}
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			problem("must be at most %d characters but is %d", *s.MaxLength, length)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			problem("%q must match %s", v, s.Pattern)
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			problem("must be at least %v but is %v", *s.Minimum, v)
+		}
+	}
+
+	return problems
+}
+
+// allowsType is true if the schema has no type or allows t. Integers are also numbers
+func (s *jsonSchema) allowsType(t string) bool {
+	return len(s.Type) == 0 || slices.Contains(s.Type, t) || t == "integer" && slices.Contains(s.Type, "number")
+}
+
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// validationProblem is a problem with an article file. Line is only set for problems in the markdown body
+type validationProblem struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (p validationProblem) String() string {
+	if p.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", p.File, p.Line, p.Message)
+	}
+	return fmt.Sprintf("%s: %s", p.File, p.Message)
+}
+
+// validationError has every problem found by validateArticles
+type validationError struct {
+	problems []validationProblem
+}
+
+func (e validationError) Error() string {
+	lines := make([]string, 0, len(e.problems))
+	for _, p := range e.problems {
+		lines = append(lines, p.String())
+	}
+	return fmt.Sprintf("found %d %s in articles:\n%s", len(e.problems), pluralize(len(e.problems), "problem", "problems"), strings.Join(lines, "\n"))
+}
+
+// byArticle groups the problems by article directory. Problems that aren't in an article, like an invalid
+// series, return the whole error since they can't be skipped one article at a time
+func (e validationError) byArticle(rootDir string) (map[string]error, error) {
+	grouped := map[string][]validationProblem{}
+	for _, p := range e.problems {
+		if p.File == rootDir {
+			return nil, e
+		}
+		dir := filepath.Dir(p.File)
+		grouped[dir] = append(grouped[dir], p)
+	}
+
+	result := map[string]error{}
+	for dir, problems := range grouped {
+		result[dir] = validationError{problems}
+	}
+	return result, nil
+}
+
+// validateArticles checks every article directory and returns a validationError with all problems
+// instead of stopping at the first. Each article.json must match the schema and each article.md
+// must not be empty, only use known liquid tags, include files that exist, and render if templates are
+// enabled. Series are checked after all articles are valid
+func validateArticles(rootDir string) error {
+	schema, err := parseSchema(articleSchema)
+	if err != nil {
+		return err
+	}
+
+	dirs, err := findArticleDirectories(rootDir)
+	if err != nil {
+		return err
+	}
+
+	renderer, err := loadArticleRenderer(rootDir)
+	if err != nil {
+		return err
+	}
+
+	var problems []validationProblem
+	articles := map[string]*Article{}
+	for _, dir := range dirs {
+		article, dirProblems := validateArticle(schema, dir)
+		problems = append(problems, dirProblems...)
+		articles[dir] = article
+
+		if renderer != nil && article != nil && len(dirProblems) == 0 {
+			body, err := os.ReadFile(filepath.Join(dir, "article.md"))
+			if err == nil {
+				_, err = renderer.render(article, string(body))
+			}
+			if err != nil {
+				problems = append(problems, validationProblem{File: filepath.Join(dir, "article.md"), Message: err.Error()})
+			}
+		}
+	}
+
+	if len(problems) == 0 {
+		_, err = buildSeriesIndex(dirs, articles)
+		if err != nil {
+			problems = append(problems, validationProblem{File: rootDir, Message: err.Error()})
+		}
+	}
+
+	if len(problems) > 0 {
+		return validationError{problems}
+	}
+
+	return nil
+}
+
+func validateArticle(schema *jsonSchema, dir string) (*Article, []validationProblem) {
+	var problems []validationProblem
+
+	markdownPath := filepath.Join(dir, "article.md")
+	body, err := os.ReadFile(markdownPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		problems = append(problems, validationProblem{File: markdownPath, Message: "missing article body"})
+	case err != nil:
+		problems = append(problems, validationProblem{File: markdownPath, Message: err.Error()})
+	case strings.TrimSpace(string(body)) == "":
+		problems = append(problems, validationProblem{File: markdownPath, Message: "article body must not be empty"})
+	default:
+		for _, p := range validateLiquidTags(string(body)) {
+			problems = append(problems, validationProblem{File: markdownPath, Line: p.Line, Message: p.Message})
+		}
+		problems = append(problems, validateIncludes(dir, string(body))...)
+	}
+
+	jsonPath := filepath.Join(dir, "article.json")
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = errors.New("missing article details")
+		}
+		return nil, append(problems, validationProblem{File: jsonPath, Message: err.Error()})
+	}
+
+	var value any
+	err = json.Unmarshal(data, &value)
+	if err != nil {
+		return nil, append(problems, validationProblem{File: jsonPath, Message: fmt.Sprintf("invalid JSON: %v", err)})
+	}
+
+	for _, p := range schema.validate("", value) {
+		problems = append(problems, validationProblem{File: jsonPath, Message: p})
+	}
+
+	var article Article
+	err = json.Unmarshal(data, &article)
+	if err != nil {
+		return nil, problems
+	}
+
+	if article.Description == descriptionPlaceholder {
+		problems = append(problems, validationProblem{File: jsonPath, Message: fmt.Sprintf("description: replace the placeholder %q written by the new command", descriptionPlaceholder)})
+	}
+
+	return &article, problems
+}
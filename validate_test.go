@@ -0,0 +1,233 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateArticle(t *testing.T) {
+	tests := []struct {
+		name             string
+		articleJSON      string
+		body             string
+		expectedProblems []string
+	}{
+		{
+			"Valid",
+			`{"title": "My Article", "description": "about things", "tags": ["go", "webdev"], "gopher": "https://example.com/gopher.png"}`,
+			"Hello",
+			nil,
+		},
+		{
+			"WrittenByCLI",
+			`{"id": 0, "slug": "", "title": "My Article", "description": "", "url": "", "tags": null, "cover_image": "", "gopher": ""}`,
+			"Hello",
+			nil,
+		},
+		{
+			"TooManyTags",
+			`{"title": "My Article", "tags": ["a", "b", "c", "d", "e"]}`,
+			"Hello",
+			[]string{"tags: must have at most 4 items but has 5"},
+		},
+		{
+			"InvalidTags",
+			`{"title": "My Article", "tags": ["Go", "web-dev", "go", "go"]}`,
+			"Hello",
+			[]string{
+				`tags[0]: "Go" must match ^[a-z0-9]+$`,
+				`tags[1]: "web-dev" must match ^[a-z0-9]+$`,
+				`tags[3]: duplicate item "go"`,
+			},
+		},
+		{
+			"MissingTitle",
+			`{"description": "about things"}`,
+			"Hello",
+			[]string{`missing required field "title"`},
+		},
+		{
+			"LongTitleAndDescription",
+			`{"title": "` + strings.Repeat("a", 129) + `", "description": "` + strings.Repeat("é", 171) + `"}`,
+			"Hello",
+			[]string{
+				"description: must be at most 170 characters but is 171",
+				"title: must be at most 128 characters but is 129",
+			},
+		},
+		{
+			"InvalidURLs",
+			`{"title": "My Article", "gopher": "gopher.png", "cover_image": "ftp://example.com/image.png"}`,
+			"Hello",
+			[]string{
+				`cover_image: "ftp://example.com/image.png" must match ^(https?://\S+)?$`,
+				`gopher: "gopher.png" must match ^(https?://\S+)?$`,
+			},
+		},
+		{
+			"WrongTypesAndUnknownField",
+			`{"id": "1234", "title": "My Article", "series_part": 1.5, "tag": ["go"]}`,
+			"Hello",
+			[]string{
+				"id: must be integer but is string",
+				"series_part: must be integer but is number",
+				`unknown field "tag"`,
+			},
+		},
+		{
+			"DescriptionPlaceholder",
+			`{"title": "My Article", "description": "` + descriptionPlaceholder + `"}`,
+			"Hello",
+			[]string{`description: replace the placeholder "TODO: describe the article" written by the new command`},
+		},
+		{
+			"EmptyBody",
+			`{"title": "My Article"}`,
+			" \n",
+			[]string{"article body must not be empty"},
+		},
+		{
+			"InvalidLiquidTag",
+			`{"title": "My Article"}`,
+			"Hello\n\n{% youtub dQw4w9WgXcQ %}\n",
+			[]string{`unknown liquid tag "youtub": did you mean "youtube"?`},
+		},
+		{
+			"InvalidJSON",
+			`{"title": "My Article",}`,
+			"Hello",
+			[]string{"invalid JSON: invalid character '}' looking for beginning of object key string"},
+		},
+	}
+
+	schema, err := parseSchema(articleSchema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			err := os.WriteFile(filepath.Join(dir, "article.json"), []byte(tt.articleJSON), 0640)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			err = os.WriteFile(filepath.Join(dir, "article.md"), []byte(tt.body), 0640)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			_, problems := validateArticle(schema, dir)
+
+			var messages []string
+			for _, p := range problems {
+				messages = append(messages, p.Message)
+			}
+			if !slices.Equal(messages, tt.expectedProblems) {
+				t.Fatalf("unexpected problems:\n%s", strings.Join(messages, "\n"))
+			}
+		})
+	}
+}
+
+func TestValidateArticles(t *testing.T) {
+	root := t.TempDir()
+	writeTestArticle(t, root, "a", &Article{Title: "A", Tags: []string{"Go"}}, "Hello")
+	writeTestArticle(t, root, "b", &Article{Title: ""}, "")
+	writeTestArticle(t, root, "c", &Article{Title: "C"}, "Hello")
+
+	err := validateArticles(root)
+
+	var validationErr validationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected validation error but got: %v", err)
+	}
+
+	expected := []validationProblem{
+		{File: filepath.Join(root, "a", "article.json"), Message: `tags[0]: "Go" must match ^[a-z0-9]+$`},
+		{File: filepath.Join(root, "b", "article.md"), Message: "article body must not be empty"},
+		{File: filepath.Join(root, "b", "article.json"), Message: "title: must not be empty"},
+	}
+	if !slices.Equal(validationErr.problems, expected) {
+		t.Fatalf("unexpected problems: %v", validationErr.problems)
+	}
+
+	if !strings.HasPrefix(err.Error(), "found 3 problems in articles:\n") {
+		t.Fatalf("unexpected error message: %v", err)
+	}
+}
+
+func TestValidateArticlesSeries(t *testing.T) {
+	root := t.TempDir()
+	writeTestArticle(t, root, "a", &Article{Title: "A", Series: "s", SeriesPart: 1}, "Hello")
+	writeTestArticle(t, root, "b", &Article{Title: "B", Series: "s", SeriesPart: 3}, "Hello")
+
+	err := validateArticles(root)
+	if err == nil || !strings.Contains(err.Error(), `series "s" is missing part 2`) {
+		t.Fatalf("expected series error but got: %v", err)
+	}
+}
+
+func TestValidateArticlesRender(t *testing.T) {
+	root := t.TempDir()
+	writeTestArticle(t, root, "a", &Article{Title: "A"}, "By {{ .Vars.author }}")
+	writeTestArticle(t, root, "b", &Article{Title: "B"}, "By {{ .Vars.typo }}")
+
+	err := os.WriteFile(filepath.Join(root, configFileName), []byte(`{"render": {"vars": {"author": "Calvin"}}}`), 0640)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = validateArticles(root)
+	if err == nil || !strings.Contains(err.Error(), "found 1 problem in articles:\n"+filepath.Join(root, "b", "article.md")) {
+		t.Fatalf("expected render error for b but got: %v", err)
+	}
+}
+
+func TestParseSchema(t *testing.T) {
+	tests := []struct {
+		name          string
+		schema        string
+		expectedError string
+	}{
+		{
+			"Annotations",
+			`{"$schema": "https://json-schema.org/draft/2020-12/schema", "$id": "article", "title": "Article", "description": "an article", "type": "object"}`,
+			"",
+		},
+		{
+			"UnknownKeyword",
+			`{"type": "string", "format": "uri"}`,
+			`json: unknown field "format"`,
+		},
+		{
+			"NestedUnknownKeyword",
+			`{"type": "object", "properties": {"tags": {"type": "array", "items": {"type": "string", "enum": ["go"]}}}}`,
+			`json: unknown field "enum"`,
+		},
+		{
+			"InvalidPattern",
+			`{"type": "string", "pattern": "("}`,
+			`error compiling pattern "("`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseSchema([]byte(tt.schema))
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+				t.Fatalf("expected error %q but got: %v", tt.expectedError, err)
+			}
+		})
+	}
+}